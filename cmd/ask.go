@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinReader is shared by everything that asks the operator a question,
+// so that no input read ahead gets lost between them.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// ask prints question on stderr and returns the line typed in reply.
+func ask(question string) (string, error) {
+	fmt.Fprintf(os.Stderr, "\033[33m%s\033[0m ", question)
+	line, err := stdinReader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
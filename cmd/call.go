@@ -4,7 +4,11 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/33arc/mcpt/mcp"
 	"github.com/spf13/cobra"
@@ -12,30 +16,74 @@ import (
 
 var tool string
 var arguments string
+var callTimeout time.Duration
+var callOutDir string
 
 // callCmd represents the call command
 var callCmd = &cobra.Command{
 	Use:   "call",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Call a tool",
+	Long: `Send tools/call for --tool with the --arguments JSON object and print the
+result. Tools may run for minutes: there is no time limit unless --timeout
+is given, and the progress the server reports is shown on stderr while the
+tool runs. When the call is interrupted with Ctrl-C or runs out of time, the
+server is sent notifications/cancelled for it.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+Text content is printed, images and audio are written to files in --out-dir,
+and resource links and embedded resources are summarised. A result with
+structuredContent is validated against the outputSchema of its tool, and a
+mismatch ends mcpt with exit status 3. Pass --output json for the raw
+result.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if tool == "" {
 			log.Fatal("Missing --tool JSON string")
 		}
 
-		client := mcp.NewClient(host, sseEnabled, protocolVersion)
-		client.Call(tool, arguments)
+		var toolArgs map[string]interface{}
+		if err := json.Unmarshal([]byte(arguments), &toolArgs); err != nil {
+			log.Fatalf("Failed to parse arguments JSON: %v", err)
+		}
+
+		ctx, cancel := interruptContext()
+		defer cancel()
+		if callTimeout > 0 {
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithTimeoutCause(ctx, callTimeout, fmt.Errorf("timed out after %s", callTimeout))
+			defer cancelTimeout()
+		}
+
+		runSession(ctx, cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "tools"); err != nil {
+				return err
+			}
+			progress := newProgressPrinter()
+			result, err := client.CallToolWithProgress(ctx, tool, toolArgs, progress.update)
+			progress.done()
+			if err != nil {
+				return err
+			}
+			if resultOutput == "json" {
+				err = printJSON(result)
+			} else {
+				err = printToolResult(result, callOutDir)
+			}
+			if err != nil {
+				return err
+			}
+			if result.IsError {
+				return &exitStatus{code: exitToolError}
+			}
+			return checkStructuredContent(ctx, client, tool, result)
+		})
 	},
 }
 
 func init() {
 	callCmd.Flags().StringVar(&tool, "tool", "", "Tool name")
 	callCmd.Flags().StringVar(&arguments, "arguments", "{}", "Json file containing arguments")
+	callCmd.Flags().StringVar(&callOutDir, "out-dir", ".", "Write images and audio to files in this directory")
+	callCmd.Flags().DurationVar(&callTimeout, "timeout", 0, "Cancel the call after this long, e.g. 30s (0 for no limit)")
+	callCmd.Flags().StringVar(&resultOutput, "output", "text", "Print the result as text or as raw json")
 	rootCmd.AddCommand(callCmd)
 
 	// Here you will define your flags and configuration settings.
@@ -48,3 +96,75 @@ func init() {
 	// is called directly, e.g.:
 	// callCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+func printToolResult(result *mcp.CallToolResult, dir string) error {
+	if result.IsError {
+		log.Printf("Tool %s reported an error", tool)
+	}
+	used := map[string]bool{}
+	for _, content := range result.Content {
+		// embedded resources are summarised, only images and audio are
+		// written to dir
+		contentDir := dir
+		if content.Type == "resource" {
+			contentDir = ""
+		}
+		if err := printContent(content, contentDir, used); err != nil {
+			return err
+		}
+	}
+	if result.StructuredContent != nil {
+		fmt.Printf("\033[34mstructuredContent:\033[0m\n")
+		return printJSON(result.StructuredContent)
+	}
+	return nil
+}
+
+// checkStructuredContent validates the structuredContent of a result
+// against the outputSchema of the tool that returned it. Results without
+// structuredContent are left alone, so that calls do not cost an extra
+// tools/list in the server's trace.
+func checkStructuredContent(ctx context.Context, client *mcp.Client, name string, result *mcp.CallToolResult) error {
+	if result.StructuredContent == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	t, err := findTool(ctx, client, name)
+	if err != nil {
+		log.Printf("Cannot validate structuredContent: %v", err)
+		return nil
+	}
+	if err := mcp.ValidateStructuredContent(t, result); err != nil {
+		log.Printf("structuredContent does not match the outputSchema of %s: %v", name, err)
+		return &exitStatus{code: exitSchemaMismatch}
+	}
+	return nil
+}
+
+// findTool lists the tools of the server page by page until it comes
+// across the one called name.
+func findTool(ctx context.Context, client *mcp.Client, name string) (*mcp.Tool, error) {
+	opts := mcp.PageOptions{MaxPages: 1}
+	seen := map[string]bool{}
+	for {
+		tools, next, err := client.ListToolsPages(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tools {
+			if tools[i].Name == name {
+				return &tools[i], nil
+			}
+		}
+		if next == "" {
+			return nil, fmt.Errorf("the server does not list tool %s", name)
+		}
+		if seen[next] {
+			return nil, fmt.Errorf("tools/list: server returned cursor %q again", next)
+		}
+		seen[next] = true
+		opts.Cursor = next
+	}
+}
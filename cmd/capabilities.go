@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+// requireCapability fails with a clear message when the server did not
+// advertise what a command needs. Attached sessions were not initialized
+// by us, so for them nothing is known and everything is tried.
+func requireCapability(client *mcp.Client, capability string) error {
+	if client.Server == nil {
+		return nil
+	}
+	caps := client.Server.Capabilities
+
+	var ok bool
+	switch capability {
+	case "tools":
+		ok = caps.Tools != nil
+	case "prompts":
+		ok = caps.Prompts != nil
+	case "resources":
+		ok = caps.Resources != nil
+	case "resources.subscribe":
+		ok = caps.Resources != nil && caps.Resources.Subscribe
+	case "completions":
+		// the capability was only introduced in 2025-03-26; older servers
+		// may still answer completion/complete
+		ok = caps.Completions != nil || client.Server.ProtocolVersion < completionsVersion
+	case "logging":
+		ok = caps.Logging != nil
+	default:
+		return fmt.Errorf("unknown capability %q", capability)
+	}
+	if !ok {
+		return fmt.Errorf("server %s does not support %s (the %s capability is missing)", serverName(client), capabilityNames[capability], capability)
+	}
+	return nil
+}
+
+// completionsVersion is the first protocol version with the completions
+// capability. Protocol versions are dates, so they compare as strings.
+const completionsVersion = "2025-03-26"
+
+var capabilityNames = map[string]string{
+	"tools":               "tools",
+	"prompts":             "prompts",
+	"resources":           "resources",
+	"resources.subscribe": "resource subscriptions",
+	"completions":         "argument completion",
+	"logging":             "logging",
+}
+
+func serverName(client *mcp.Client) string {
+	info := client.Server.ServerInfo
+	if info.Version == "" {
+		return info.Name
+	}
+	return info.Name + " " + info.Version
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var completePrompt string
+var completeTemplate string
+var completeArg string
+var completeValue string
+var completeContext []string
+
+var completeCmd = &cobra.Command{
+	Use:   "complete",
+	Short: "Complete an argument of a prompt or resource template",
+	Long: `Send completion/complete for the argument --arg of --prompt or of
+--resource-template, with --value as what has been typed so far, and print
+the suggested values followed by total and hasMore. Arguments that are
+resolved already are passed with --context name=value.
+
+Pass --output json for the raw result.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var ref mcp.CompletionReference
+		switch {
+		case completePrompt != "" && completeTemplate != "":
+			log.Fatal("Use either --prompt or --resource-template")
+		case completePrompt != "":
+			ref = mcp.CompletionReference{Type: "ref/prompt", Name: completePrompt}
+		case completeTemplate != "":
+			ref = mcp.CompletionReference{Type: "ref/resource", URI: completeTemplate}
+		default:
+			log.Fatal("Missing --prompt or --resource-template")
+		}
+		if completeArg == "" {
+			log.Fatal("Missing --arg")
+		}
+		resolved, err := parseKeyValues(completeContext, "--context")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "completions"); err != nil {
+				return err
+			}
+			result, err := client.Complete(ctx, ref, completeArg, completeValue, resolved)
+			if err != nil {
+				return err
+			}
+			if resultOutput == "json" {
+				return printJSON(result)
+			}
+
+			for _, value := range result.Completion.Values {
+				fmt.Println(value)
+			}
+			total := "unknown"
+			if result.Completion.Total != nil {
+				total = fmt.Sprint(*result.Completion.Total)
+			}
+			fmt.Printf("\033[34mtotal: %s, hasMore: %t\033[0m\n", total, result.Completion.HasMore)
+			return nil
+		})
+	},
+}
+
+func init() {
+	completeCmd.Flags().StringVar(&completePrompt, "prompt", "", "Name of the prompt")
+	completeCmd.Flags().StringVar(&completeTemplate, "resource-template", "", "URI template of the resource")
+	completeCmd.Flags().StringVar(&completeArg, "arg", "", "Name of the argument to complete")
+	completeCmd.Flags().StringVar(&completeValue, "value", "", "Partial value of the argument")
+	completeCmd.Flags().StringArrayVar(&completeContext, "context", nil, "Resolved argument as name=value (repeatable)")
+	completeCmd.Flags().StringVar(&resultOutput, "output", "text", "Print the result as text or as raw json")
+	rootCmd.AddCommand(completeCmd)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+// printContent renders one content block of a prompt message or a tool
+// result. Text goes to stdout; images, audio and embedded resources are
+// summarised, or written to files when dir is set.
+func printContent(content mcp.Content, dir string, used map[string]bool) error {
+	switch content.Type {
+	case "text":
+		fmt.Println(content.Text)
+	case "image", "audio":
+		data, err := base64.StdEncoding.DecodeString(content.Data)
+		if err != nil {
+			return fmt.Errorf("invalid %s data: %w", content.Type, err)
+		}
+		if dir == "" {
+			fmt.Printf("[%s %s, %d bytes, use --out-dir to save it]\n", content.Type, content.MimeType, len(data))
+			return nil
+		}
+		file, err := writeContent(mcp.ResourceContents{URI: content.Type, MimeType: content.MimeType, Blob: content.Data}, dir, used)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[%s %s -> %s]\n", content.Type, content.MimeType, file)
+	case "resource_link":
+		fmt.Printf("[link %s]\n", describeLink(content))
+	case "resource":
+		if content.Resource == nil {
+			return fmt.Errorf("resource content without a resource")
+		}
+		resource := *content.Resource
+		if dir != "" {
+			file, err := writeContent(resource, dir, used)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("[resource %s -> %s]\n", resource.URI, file)
+			return nil
+		}
+		if resource.Blob != "" {
+			size := base64.StdEncoding.DecodedLen(len(resource.Blob))
+			fmt.Printf("[resource %s (%s), about %d bytes of binary content]\n", resource.URI, resource.MimeType, size)
+			return nil
+		}
+		fmt.Printf("[resource %s]\n", resource.URI)
+		fmt.Println(resource.Text)
+	default:
+		fmt.Printf("[unsupported content type %q]\n", content.Type)
+	}
+	return nil
+}
+
+func describeLink(content mcp.Content) string {
+	parts := []string{content.URI}
+	name := content.Title
+	if name == "" {
+		name = content.Name
+	}
+	if name != "" {
+		parts = append(parts, fmt.Sprintf("%q", name))
+	}
+	if content.MimeType != "" {
+		parts = append(parts, "("+content.MimeType+")")
+	}
+	if content.Description != "" {
+		parts = append(parts, "- "+content.Description)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// printLineDiff prints the lines removed from old in red and the lines
+// added in new in green, leaving out those both share.
+func printLineDiff(old, new string) {
+	a := strings.Split(old, "\n")
+	b := strings.Split(new, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Printf("\033[31m- %s\033[0m\n", a[i])
+			i++
+		default:
+			fmt.Printf("\033[32m+ %s\033[0m\n", b[j])
+			j++
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+// display prints a feature list in the format chosen with --output.
+func display(client *mcp.Client, features interface{}, output string) error {
+	if output == "json" {
+		return printJSON(features)
+	}
+	if output == "call" {
+		tools, ok := features.([]mcp.Tool)
+		if !ok {
+			return fmt.Errorf("--output call only applies to tools")
+		}
+		return printCallHints(client, tools)
+	}
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	vJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	fmt.Println(string(vJSON))
+	return nil
+}
+
+// printCallHints prints, for every tool, the mcpt call command that runs it
+// and the arguments it takes.
+func printCallHints(client *mcp.Client, tools []mcp.Tool) error {
+	for _, tool := range tools {
+		inputSchema := tool.InputSchema
+		if inputSchema == nil {
+			return fmt.Errorf("inputSchema not found for tool %s", tool.Name)
+		}
+
+		required, ok := inputSchema["required"].([]interface{})
+		if !ok {
+			required = []interface{}{}
+		}
+
+		properties, ok := inputSchema["properties"].(map[string]interface{})
+		if !ok {
+			properties = map[string]interface{}{}
+		}
+
+		toolName := strings.TrimSpace(tool.Name)
+
+		argument := ""
+		requiredSet := makeSet(required)
+		for _, key := range required {
+			keyStr := strings.TrimSpace(key.(string))
+
+			// type-assert the property to a map
+			prop, ok := properties[keyStr].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("property %s is not a map", keyStr)
+			}
+
+			// now access the "type" field
+			val, ok := prop["type"]
+			if !ok {
+				return fmt.Errorf("type not found for property %s", keyStr)
+			}
+
+			if val == "object" {
+				argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, keyStr+"Object")
+			} else if val == "enum" {
+				argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, keyStr+"Enum")
+			} else {
+				argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, val)
+			}
+		}
+
+		// Only remove the last comma if argument is non-empty
+		if len(argument) > 0 {
+			argument = argument[:len(argument)-1]
+		}
+		fmt.Printf("mcpt call %s --tool '%s' --arguments '{%s}'\n", target(client), toolName, argument)
+		traverseProperties(properties, "", requiredSet)
+		fmt.Printf("\n")
+	}
+	return nil
+}
+
+// target gives the flags that reach the client's server again.
+func target(client *mcp.Client) string {
+	if len(client.Command) > 0 {
+		return fmt.Sprintf("--command '%s'", strings.Join(client.Command, " "))
+	}
+	target := fmt.Sprintf("--host '%s'", client.Host)
+	if client.TransportName == mcp.TransportSSE {
+		target = "--transport sse " + target
+	}
+	if client.UnixSocket != "" {
+		target = fmt.Sprintf("--unix-socket '%s' %s", client.UnixSocket, target)
+	}
+	return target
+}
+
+func makeSet(arr []interface{}) map[string]struct{} {
+	m := make(map[string]struct{}, len(arr))
+	for _, v := range arr {
+		if s, ok := v.(string); ok {
+			m[s] = struct{}{}
+		}
+	}
+	return m
+}
+
+func traverseProperties(properties map[string]interface{}, prefix string, required map[string]struct{}) {
+	for key, val := range properties {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+
+		propMap, ok := val.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// print type if exists
+		if typeVal, ok := propMap["type"]; ok {
+			var t string
+			t = fmt.Sprintf("%v", typeVal) // convert interface{} → string
+			if enumVal, ok := propMap["enum"]; ok {
+				if enumArr, ok := enumVal.([]interface{}); ok {
+					t = "enum("
+					for i, e := range enumArr {
+						if i > 0 {
+							t += ","
+						}
+						t += "\"" + (e.(string)) + "\""
+					}
+					t += ")"
+				}
+			}
+			if _, exists := required[fullKey]; exists {
+				fmt.Printf("\033[31m[REQUIRED]\033[0m\033[34m %s -> type: %s\033[0m", fullKey, t)
+			} else {
+				fmt.Printf("           \033[34m%s -> type: %s\033[0m", fullKey, t)
+			}
+			if arrVal, ok := propMap["items"].(map[string]interface{}); ok {
+				for key, val := range arrVal {
+					// fmt.Printf("ArrItem %s", val)
+					if key == "type" {
+						fmt.Printf("\033[34m[ArrayItems -> type: %s]\033[0m", val)
+					}
+				}
+				_, ok := propMap["uniqueItems"]
+				if ok {
+					fmt.Printf("[UNIQUE]")
+				}
+			}
+			fmt.Printf("\n")
+
+		}
+
+		// if nested properties, recurse
+		if nested, ok := propMap["properties"]; ok {
+			if nestedProps, ok := nested.(map[string]interface{}); ok {
+				traverseProperties(nestedProps, fullKey, required)
+			}
+		}
+	}
+}
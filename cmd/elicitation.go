@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/33arc/mcpt/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+var elicitAnswers string
+var elicitValues []string
+var elicitAction string
+
+// newElicitationHandler returns the elicitation responder for this run:
+// the answers given on the command line when there are any, otherwise a
+// form on the terminal. Without a terminal elicitation is not advertised.
+func newElicitationHandler() (mcp.ElicitationHandler, error) {
+	switch elicitAction {
+	case "", mcp.ElicitAccept, mcp.ElicitDecline, mcp.ElicitCancel:
+	default:
+		return nil, fmt.Errorf("unknown --elicit-action %q, use accept, decline or cancel", elicitAction)
+	}
+
+	if elicitAnswers == "" && len(elicitValues) == 0 && elicitAction == "" {
+		if isTerminal(os.Stdin) {
+			return interactiveElicitor, nil
+		}
+		return nil, nil
+	}
+
+	answers := map[string]interface{}{}
+	if elicitAnswers != "" {
+		data, err := os.ReadFile(elicitAnswers)
+		if err != nil {
+			return nil, err
+		}
+		// YAML is a superset of JSON, so this reads either
+		if err := yaml.Unmarshal(data, &answers); err != nil {
+			return nil, fmt.Errorf("invalid elicitation answers %s: %w", elicitAnswers, err)
+		}
+	}
+	values, err := parseKeyValues(elicitValues, "--elicit")
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		answers[key] = value
+	}
+
+	action := elicitAction
+	if action == "" {
+		action = mcp.ElicitAccept
+	}
+	return fixedElicitor(answers, action), nil
+}
+
+// fixedElicitor answers every elicitation with the same action and, when
+// accepting, fills in the form from answers. A form that the answers do
+// not fill in validly is cancelled.
+func fixedElicitor(answers map[string]interface{}, action string) mcp.ElicitationHandler {
+	return func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+		log.Printf("Elicitation: %s", req.Message)
+		if action != mcp.ElicitAccept {
+			log.Printf("Elicitation: answering %s", action)
+			return &mcp.ElicitResult{Action: action}, nil
+		}
+
+		schema := &req.RequestedSchema
+		content := map[string]interface{}{}
+		for _, name := range fieldOrder(schema) {
+			answer, ok := answers[name]
+			if !ok {
+				if schema.IsRequired(name) {
+					log.Printf("Elicitation: no answer for the required field %s, cancelling", name)
+					return &mcp.ElicitResult{Action: mcp.ElicitCancel}, nil
+				}
+				continue
+			}
+			value, err := checkElicitValue(schema.Properties[name], answer)
+			if err != nil {
+				log.Printf("Elicitation: invalid answer for %s: %v, cancelling", name, err)
+				return &mcp.ElicitResult{Action: mcp.ElicitCancel}, nil
+			}
+			content[name] = value
+		}
+		for name := range answers {
+			if _, ok := schema.Properties[name]; !ok {
+				log.Printf("Elicitation: the form has no field %s", name)
+			}
+		}
+		return &mcp.ElicitResult{Action: mcp.ElicitAccept, Content: content}, nil
+	}
+}
+
+// errElicitCancelled ends a form the operator gave up on.
+var errElicitCancelled = errors.New("cancelled")
+
+// interactiveElicitor asks the operator to fill in the form on the
+// terminal, one field at a time, checking every answer against its schema.
+func interactiveElicitor(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	fmt.Fprintf(os.Stderr, "\033[34mThe server asks: %s\033[0m\n", req.Message)
+
+	for {
+		answer, err := ask("Fill in the form? (a)ccept, (d)ecline or (c)ancel [a]:")
+		if err != nil && answer == "" {
+			return &mcp.ElicitResult{Action: mcp.ElicitCancel}, nil
+		}
+		switch strings.ToLower(answer) {
+		case "", "a", "accept":
+		case "d", "decline":
+			return &mcp.ElicitResult{Action: mcp.ElicitDecline}, nil
+		case "c", "cancel":
+			return &mcp.ElicitResult{Action: mcp.ElicitCancel}, nil
+		default:
+			continue
+		}
+		break
+	}
+
+	schema := &req.RequestedSchema
+	content := map[string]interface{}{}
+	for _, name := range fieldOrder(schema) {
+		value, err := askField(name, schema.Properties[name], schema.IsRequired(name))
+		if err != nil {
+			return &mcp.ElicitResult{Action: mcp.ElicitCancel}, nil
+		}
+		if value != nil {
+			content[name] = value
+		}
+	}
+	return &mcp.ElicitResult{Action: mcp.ElicitAccept, Content: content}, nil
+}
+
+// askField asks for one field until the answer is valid. It returns nil
+// for an optional field left empty.
+func askField(name string, prop *mcp.PrimitiveSchema, required bool) (interface{}, error) {
+	label := name
+	if prop.Title != "" {
+		label = prop.Title
+	}
+	if prop.Description != "" {
+		fmt.Fprintf(os.Stderr, "\033[90m%s\033[0m\n", prop.Description)
+	}
+	for i, value := range prop.Enum {
+		option := value
+		if i < len(prop.EnumNames) {
+			option = fmt.Sprintf("%s (%s)", prop.EnumNames[i], value)
+		}
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, option)
+	}
+
+	hints := []string{prop.Type}
+	if prop.Type == "boolean" {
+		hints[0] = "y/n"
+	}
+	if len(prop.Enum) > 0 {
+		hints[0] = "pick a number or value"
+	}
+	if required {
+		hints = append(hints, "required")
+	}
+	if prop.Default != nil {
+		hints = append(hints, fmt.Sprintf("default %v", prop.Default))
+	}
+	question := fmt.Sprintf("%s (%s):", label, strings.Join(hints, ", "))
+
+	for {
+		answer, err := ask(question)
+		if err != nil && answer == "" {
+			return nil, errElicitCancelled
+		}
+		answer = strings.TrimSpace(answer)
+
+		if answer == "" {
+			if prop.Default != nil {
+				return prop.Default, nil
+			}
+			if !required {
+				return nil, nil
+			}
+			fmt.Fprintln(os.Stderr, "\033[31mA value is required\033[0m")
+			continue
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(prop.Enum) {
+			answer = prop.Enum[n-1]
+		}
+		if prop.Type == "boolean" {
+			switch strings.ToLower(answer) {
+			case "y", "yes":
+				answer = "true"
+			case "n", "no":
+				answer = "false"
+			}
+		}
+
+		value, err := checkElicitValue(prop, answer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\033[31m%v\033[0m\n", err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// fieldOrder returns the fields of a form in the order to ask for them.
+func fieldOrder(schema *mcp.ElicitSchema) []string {
+	if len(schema.Order) == len(schema.Properties) {
+		return schema.Order
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkElicitValue converts an answer to the type of its field, parsing
+// strings given on the command line or typed in, and checks it against
+// the constraints of the field.
+func checkElicitValue(prop *mcp.PrimitiveSchema, answer interface{}) (interface{}, error) {
+	if prop == nil {
+		return nil, errors.New("unknown field")
+	}
+	text, isText := answer.(string)
+
+	switch prop.Type {
+	case "string":
+		if !isText {
+			return nil, fmt.Errorf("%v is not a string", answer)
+		}
+		return text, checkString(prop, text)
+	case "boolean":
+		if isText {
+			b, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a boolean", text)
+			}
+			return b, nil
+		}
+		if b, ok := answer.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("%v is not a boolean", answer)
+	case "number", "integer":
+		var n float64
+		switch v := answer.(type) {
+		case string:
+			var err error
+			if n, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("%q is not a number", v)
+			}
+		case int:
+			n = float64(v)
+		case float64:
+			n = v
+		default:
+			return nil, fmt.Errorf("%v is not a number", answer)
+		}
+		if prop.Type == "integer" && n != math.Trunc(n) {
+			return nil, fmt.Errorf("%v is not an integer", n)
+		}
+		if prop.Minimum != nil && n < *prop.Minimum {
+			return nil, fmt.Errorf("%v is less than the minimum %v", n, *prop.Minimum)
+		}
+		if prop.Maximum != nil && n > *prop.Maximum {
+			return nil, fmt.Errorf("%v is more than the maximum %v", n, *prop.Maximum)
+		}
+		if prop.Type == "integer" {
+			return int64(n), nil
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported field type %q", prop.Type)
+}
+
+func checkString(prop *mcp.PrimitiveSchema, text string) error {
+	if len(prop.Enum) > 0 && !slices.Contains(prop.Enum, text) {
+		return fmt.Errorf("%q is not one of %s", text, strings.Join(prop.Enum, ", "))
+	}
+	length := utf8.RuneCountInString(text)
+	if prop.MinLength != nil && length < *prop.MinLength {
+		return fmt.Errorf("needs at least %d characters", *prop.MinLength)
+	}
+	if prop.MaxLength != nil && length > *prop.MaxLength {
+		return fmt.Errorf("allows at most %d characters", *prop.MaxLength)
+	}
+
+	var err error
+	switch prop.Format {
+	case "email":
+		_, err = mail.ParseAddress(text)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(text); err == nil && u.Scheme == "" {
+			err = errors.New("missing scheme")
+		}
+	case "date":
+		_, err = time.Parse(time.DateOnly, text)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, text)
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a valid %s", text, prop.Format)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+func TestCheckElicitValue(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	floatPtr := func(n float64) *float64 { return &n }
+
+	tests := []struct {
+		name    string
+		prop    *mcp.PrimitiveSchema
+		answer  interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "unknown field", answer: "x", wantErr: "unknown field"},
+		{name: "string", prop: &mcp.PrimitiveSchema{Type: "string"}, answer: "hi", want: "hi"},
+		{name: "string from JSON number", prop: &mcp.PrimitiveSchema{Type: "string"}, answer: 3.0, wantErr: "is not a string"},
+		{name: "enum value", prop: &mcp.PrimitiveSchema{Type: "string", Enum: []string{"red", "blue"}}, answer: "blue", want: "blue"},
+		{name: "not an enum value", prop: &mcp.PrimitiveSchema{Type: "string", Enum: []string{"red", "blue"}}, answer: "green", wantErr: `"green" is not one of red, blue`},
+		{name: "too short", prop: &mcp.PrimitiveSchema{Type: "string", MinLength: intPtr(3)}, answer: "ab", wantErr: "at least 3 characters"},
+		{name: "length counts characters", prop: &mcp.PrimitiveSchema{Type: "string", MaxLength: intPtr(3)}, answer: "äöü", want: "äöü"},
+		{name: "too long", prop: &mcp.PrimitiveSchema{Type: "string", MaxLength: intPtr(3)}, answer: "abcd", wantErr: "at most 3 characters"},
+		{name: "email", prop: &mcp.PrimitiveSchema{Type: "string", Format: "email"}, answer: "a@example.com", want: "a@example.com"},
+		{name: "bad email", prop: &mcp.PrimitiveSchema{Type: "string", Format: "email"}, answer: "nobody", wantErr: "not a valid email"},
+		{name: "uri without scheme", prop: &mcp.PrimitiveSchema{Type: "string", Format: "uri"}, answer: "example.com", wantErr: "not a valid uri"},
+		{name: "date", prop: &mcp.PrimitiveSchema{Type: "string", Format: "date"}, answer: "2025-06-18", want: "2025-06-18"},
+		{name: "bad date", prop: &mcp.PrimitiveSchema{Type: "string", Format: "date"}, answer: "18.06.2025", wantErr: "not a valid date"},
+		{name: "date-time", prop: &mcp.PrimitiveSchema{Type: "string", Format: "date-time"}, answer: "2025-06-18T10:00:00Z", want: "2025-06-18T10:00:00Z"},
+		{name: "boolean from text", prop: &mcp.PrimitiveSchema{Type: "boolean"}, answer: "true", want: true},
+		{name: "boolean from JSON", prop: &mcp.PrimitiveSchema{Type: "boolean"}, answer: false, want: false},
+		{name: "bad boolean", prop: &mcp.PrimitiveSchema{Type: "boolean"}, answer: "maybe", wantErr: `"maybe" is not a boolean`},
+		{name: "number from text", prop: &mcp.PrimitiveSchema{Type: "number"}, answer: "2.5", want: 2.5},
+		{name: "number from JSON", prop: &mcp.PrimitiveSchema{Type: "number"}, answer: 2.5, want: 2.5},
+		{name: "bad number", prop: &mcp.PrimitiveSchema{Type: "number"}, answer: "two", wantErr: `"two" is not a number`},
+		{name: "integer", prop: &mcp.PrimitiveSchema{Type: "integer"}, answer: "42", want: int64(42)},
+		{name: "integer from JSON", prop: &mcp.PrimitiveSchema{Type: "integer"}, answer: 42.0, want: int64(42)},
+		{name: "fraction for integer", prop: &mcp.PrimitiveSchema{Type: "integer"}, answer: "4.2", wantErr: "is not an integer"},
+		{name: "below minimum", prop: &mcp.PrimitiveSchema{Type: "number", Minimum: floatPtr(1)}, answer: "0.5", wantErr: "less than the minimum 1"},
+		{name: "at maximum", prop: &mcp.PrimitiveSchema{Type: "integer", Maximum: floatPtr(10)}, answer: 10, want: int64(10)},
+		{name: "above maximum", prop: &mcp.PrimitiveSchema{Type: "integer", Maximum: floatPtr(10)}, answer: "11", wantErr: "more than the maximum 10"},
+		{name: "unsupported type", prop: &mcp.PrimitiveSchema{Type: "array"}, answer: "x", wantErr: `unsupported field type "array"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkElicitValue(tt.prop, tt.answer)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkElicitValue failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show what the server answered to initialize",
+	Long: `Initialize a new session and print the server's name, title and version,
+the negotiated protocol version, its capabilities and its instructions.
+A saved session is not attached to and --session-id is refused, as the
+initialize result of an existing session is unknown.
+
+Pass --output json for the raw initialize result.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkLogLevel(logLevel); err != nil {
+			log.Fatal(err)
+		}
+		if sessionID != "" {
+			log.Fatal("--session-id cannot be used with info, which always initializes a new session")
+		}
+
+		ctx, cancel := interruptContext()
+		defer cancel()
+
+		client, err := dialClient(cmd, args)
+		if err != nil {
+			log.Fatalf("Failed to connect: %v", err)
+		}
+		err = startSession(ctx, client)
+		if err == nil {
+			if resultOutput == "json" {
+				err = printJSON(client.Server.Raw)
+			} else {
+				printInfo(client)
+			}
+		}
+		if cerr := client.Close(); cerr != nil {
+			log.Printf("Failed to close session: %v", cerr)
+		}
+		exitOnError(err)
+	},
+}
+
+func init() {
+	infoCmd.Flags().StringVar(&resultOutput, "output", "text", "Print the result as text or as raw json")
+	rootCmd.AddCommand(infoCmd)
+}
+
+func printInfo(client *mcp.Client) {
+	result := client.Server
+	info := result.ServerInfo
+
+	name := info.Name
+	if info.Title != "" {
+		name = fmt.Sprintf("%s (%s)", info.Title, info.Name)
+	}
+	fmt.Printf("\033[34mServer:\033[0m       %s %s\n", name, info.Version)
+
+	version := result.ProtocolVersion
+	if version != client.ProtocolVersion {
+		version += fmt.Sprintf(" \033[31m(requested %s)\033[0m", client.ProtocolVersion)
+	}
+	fmt.Printf("\033[34mProtocol:\033[0m     %s\n", version)
+
+	transport := client.TransportName
+	if client.SID != "" {
+		transport += ", session " + client.SID
+	}
+	fmt.Printf("\033[34mTransport:\033[0m    %s\n", transport)
+
+	fmt.Printf("\033[34mCapabilities:\033[0m\n")
+	printCapabilities(result.Raw)
+
+	if result.Instructions != "" {
+		fmt.Printf("\033[34mInstructions:\033[0m\n")
+		for _, line := range strings.Split(strings.TrimRight(result.Instructions, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// printCapabilities prints one capability per line, followed by the
+// options that are switched on for it. It reads them from the raw
+// initialize result so that capabilities unknown to mcpt show up too.
+func printCapabilities(raw json.RawMessage) {
+	var result struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	json.Unmarshal(raw, &result)
+	tree := result.Capabilities
+	if len(tree) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		options, _ := tree[name].(map[string]interface{})
+		var enabled []string
+		var other []string
+		for option, value := range options {
+			if value == true {
+				enabled = append(enabled, option)
+			} else if value != false {
+				other = append(other, fmt.Sprintf("%s=%s", option, jsonValue(value)))
+			}
+		}
+		sort.Strings(enabled)
+		sort.Strings(other)
+		line := fmt.Sprintf("  %-12s %s", name, strings.Join(append(enabled, other...), ", "))
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
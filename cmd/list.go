@@ -4,33 +4,49 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"log"
+
+	"github.com/33arc/mcpt/mcp"
 	"github.com/spf13/cobra"
 )
 
+var cursor string
+var pageLimit int
+var noFollow bool
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "List the tools, prompts, resources or resource templates of the server",
+	Long: `List the tools, prompts, resources or resource templates of the server.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+Paginated lists are followed to the end by default. --cursor starts at a
+given page, --page-size-limit stops following cursors once that many items
+have been listed and --no-follow fetches a single page; the cursor to
+continue from is then logged. MCP has no way to ask the server for smaller
+pages, so the page that reaches --page-size-limit is listed whole.`,
 	Run: func(cmd *cobra.Command, args []string) {
 	},
 }
 
 func init() {
+	listCmd.PersistentFlags().StringVar(&cursor, "cursor", "", "Start at the page of this cursor")
+	listCmd.PersistentFlags().IntVar(&pageLimit, "page-size-limit", 0, "Stop following cursors once this many items are listed (0 for no limit)")
+	listCmd.PersistentFlags().BoolVar(&noFollow, "no-follow", false, "Fetch a single page only")
 	rootCmd.AddCommand(listCmd)
+}
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// listCmd.PersistentFlags().String("foo", "", "A help for foo")
+func pageOptions() mcp.PageOptions {
+	opts := mcp.PageOptions{Cursor: cursor, MaxItems: pageLimit}
+	if noFollow {
+		opts.MaxPages = 1
+	}
+	return opts
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+// reportNextCursor tells how to continue a listing that stopped early.
+func reportNextCursor(next string) {
+	if next != "" {
+		log.Printf("More pages available, continue with --cursor %q", next)
+	}
 }
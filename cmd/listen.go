@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Print the messages the server sends on its own",
+	Long: `Initialize a session and keep it open, printing every notification and
+request the server sends on its own initiative (log messages, list_changed,
+resource updates, ...) until interrupted. Over Streamable HTTP this opens
+the optional GET stream of the session.
+
+Use --output json for one JSON-RPC message per line, or --output text.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := interruptContext()
+		defer stop()
+
+		runSession(ctx, cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			err := client.Listen(ctx, func(msg *mcp.JSONRPCMessage) {
+				printMessage(msg, output)
+			})
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		})
+	},
+}
+
+// printMessage prints one message received from the server, either as a
+// JSON line or as a timestamped line for people.
+func printMessage(msg *mcp.JSONRPCMessage, output string) {
+	if output == "json" {
+		msgJSON, err := json.Marshal(msg)
+		if err != nil {
+			log.Printf("Failed to marshal message: %v", err)
+			return
+		}
+		fmt.Println(string(msgJSON))
+		return
+	}
+
+	kind := "notification"
+	if len(msg.ID) > 0 {
+		kind = "request " + string(msg.ID)
+	}
+	fmt.Printf("%s \033[34m%s\033[0m %s %s\n", time.Now().Format("15:04:05.000"), kind, msg.Method, bytes.TrimSpace(msg.Params))
+}
+
+func init() {
+	rootCmd.AddCommand(listenCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+var logLevel string
+
+// logLevelColors colour the levels of server log messages; anything from
+// error upwards is red.
+var logLevelColors = map[string]string{
+	"debug":   "\033[90m",
+	"info":    "\033[34m",
+	"notice":  "\033[36m",
+	"warning": "\033[33m",
+}
+
+func checkLogLevel(level string) error {
+	if level != "" && !slices.Contains(mcp.LogLevels, level) {
+		return fmt.Errorf("unknown --log-level %q, use one of %s", level, strings.Join(mcp.LogLevels, ", "))
+	}
+	return nil
+}
+
+// printLogMessage writes a log message of the server to stderr.
+func printLogMessage(logMsg *mcp.LogMessage) {
+	color, ok := logLevelColors[logMsg.Level]
+	if !ok {
+		color = "\033[31m"
+	}
+
+	// a plain string is printed as is, anything else as compact JSON
+	var text string
+	if err := json.Unmarshal(logMsg.Data, &text); err != nil {
+		text = string(logMsg.Data)
+	}
+
+	logger := ""
+	if logMsg.Logger != "" {
+		logger = " " + logMsg.Logger
+	}
+	fmt.Fprintf(os.Stderr, "%s[%s]%s\033[0m %s\n", color, logMsg.Level, logger, text)
+}
@@ -1,18 +1,26 @@
 package cmd
 
 import (
+	"context"
+	"log"
+
 	"github.com/33arc/mcpt/mcp"
 	"github.com/spf13/cobra"
 )
 
 var pingCmd = &cobra.Command{
 	Use:   "ping",
-	Short: "Ping the MCP server over HTTP",
-	Long:  "Send a ping request to the MCP server over HTTP to verify connectivity.",
+	Short: "Ping the MCP server",
+	Long:  "Send a ping request to the MCP server to verify connectivity.",
 
 	Run: func(cmd *cobra.Command, args []string) {
-		client := mcp.NewClient(host, sseEnabled, protocolVersion)
-		client.Ping()
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := client.Ping(ctx); err != nil {
+				return err
+			}
+			log.Println("Ping OK ✅")
+			return nil
+		})
 	},
 }
 
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+const progressBarWidth = 30
+
+// progressPrinter shows the progress notifications of a request on stderr:
+// as a bar redrawn in place when run in a terminal, otherwise as one line
+// per notification.
+type progressPrinter struct {
+	bar   bool
+	drawn bool
+}
+
+func newProgressPrinter() *progressPrinter {
+	return &progressPrinter{bar: isTerminal(os.Stdout) && isTerminal(os.Stderr)}
+}
+
+func (p *progressPrinter) update(progress *mcp.Progress) {
+	status := fmt.Sprintf("%g", progress.Progress)
+	if progress.Total > 0 {
+		status = fmt.Sprintf("%g/%g", progress.Progress, progress.Total)
+	}
+	if progress.Message != "" {
+		status += " " + progress.Message
+	}
+
+	if !p.bar {
+		fmt.Fprintf(os.Stderr, "progress %s\n", status)
+		return
+	}
+
+	if progress.Total > 0 {
+		ratio := min(max(progress.Progress/progress.Total, 0), 1)
+		filled := int(ratio * progressBarWidth)
+		bar := strings.Repeat("#", filled) + strings.Repeat(".", progressBarWidth-filled)
+		fmt.Fprintf(os.Stderr, "\r\033[K\033[34m[%s] %3.0f%%\033[0m %s", bar, ratio*100, status)
+	} else {
+		fmt.Fprintf(os.Stderr, "\r\033[K\033[34m[working]\033[0m %s", status)
+	}
+	p.drawn = true
+}
+
+// done ends the line of the bar, so that what is printed next starts on a
+// line of its own.
+func (p *progressPrinter) done() {
+	if p.drawn {
+		fmt.Fprintln(os.Stderr)
+		p.drawn = false
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var promptName string
+var promptArgs []string
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Work with the prompts of a server",
+}
+
+var promptGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Render a prompt",
+	Long: `Send prompts/get for --name with the --arg name=value arguments and print
+the returned messages as a transcript, one role at a time. Required arguments
+of the prompt are checked before the request is sent. Images, audio and
+embedded resources are summarised, or written to files with --out-dir.
+
+Pass --output json for the raw result.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if promptName == "" {
+			log.Fatal("Missing --name")
+		}
+		values, err := parseKeyValues(promptArgs, "--arg")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "prompts"); err != nil {
+				return err
+			}
+			prompts, err := client.ListPrompts(ctx)
+			if err != nil {
+				return err
+			}
+			if err := checkPromptArgs(prompts, promptName, values); err != nil {
+				return err
+			}
+
+			result, err := client.GetPrompt(ctx, promptName, values)
+			if err != nil {
+				return err
+			}
+			if resultOutput == "json" {
+				return printJSON(result)
+			}
+			return printTranscript(result, outDir)
+		})
+	},
+}
+
+func init() {
+	promptGetCmd.Flags().StringVar(&promptName, "name", "", "Prompt name")
+	promptGetCmd.Flags().StringArrayVar(&promptArgs, "arg", nil, "Prompt argument as name=value (repeatable)")
+	promptGetCmd.Flags().StringVar(&outDir, "out-dir", "", "Write images, audio and embedded resources to files in this directory")
+	promptGetCmd.Flags().StringVar(&resultOutput, "output", "text", "Print the result as text or as raw json")
+	promptCmd.AddCommand(promptGetCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+// checkPromptArgs fails when a required argument of the prompt is missing
+// and warns about arguments the prompt does not declare.
+func checkPromptArgs(prompts []mcp.Prompt, name string, values map[string]string) error {
+	for _, prompt := range prompts {
+		if prompt.Name != name {
+			continue
+		}
+
+		declared := map[string]bool{}
+		var missing []string
+		for _, arg := range prompt.Arguments {
+			declared[arg.Name] = true
+			if _, ok := values[arg.Name]; arg.Required && !ok {
+				missing = append(missing, arg.Name)
+			}
+		}
+		for key := range values {
+			if !declared[key] {
+				log.Printf("Prompt %s has no argument %s", name, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("missing required arguments of prompt %s: %s", name, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+
+	names := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		names = append(names, prompt.Name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown prompt %s, the server has: %s", name, strings.Join(names, ", "))
+}
+
+func printTranscript(result *mcp.GetPromptResult, dir string) error {
+	if result.Description != "" {
+		fmt.Printf("# %s\n\n", result.Description)
+	}
+	used := map[string]bool{}
+	for i, message := range result.Messages {
+		// consecutive messages of the same role share one heading
+		if i == 0 || result.Messages[i-1].Role != message.Role {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("\033[34m[%s]\033[0m\n", message.Role)
+		}
+		if err := printContent(message.Content, dir, used); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
+
 	"github.com/33arc/mcpt/mcp"
 	"github.com/spf13/cobra"
 )
@@ -18,8 +20,17 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		client := mcp.NewClient(host, sseEnabled, protocolVersion)
-		client.ListFeature("prompts", output)
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "prompts"); err != nil {
+				return err
+			}
+			prompts, next, err := client.ListPromptsPages(ctx, pageOptions())
+			if err != nil {
+				return err
+			}
+			defer reportNextCursor(next)
+			return display(client, prompts, output)
+		})
 	},
 }
 
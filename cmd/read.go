@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var resourceURI string
+var uriTemplate string
+var templateVars []string
+var outDir string
+
+// resultOutput is the local --output of the commands that print a single
+// result, which unlike the list commands default to text.
+var resultOutput string
+
+var readCmd = &cobra.Command{
+	Use:   "read",
+	Short: "Read a resource",
+	Long: `Send resources/read for --uri and print the text contents it returns,
+each with its mimeType. Instead of --uri, --template and --var key=value
+expand an RFC 6570 URI template, as listed by mcpt list resource-templates.
+Binary (blob) contents are base64-decoded and, like every other content,
+written to a file of its own when --out-dir is given.
+
+Pass --output json for the raw result.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case resourceURI != "" && uriTemplate != "":
+			log.Fatal("Use either --uri or --template")
+		case uriTemplate != "":
+			vars, err := parseKeyValues(templateVars, "--var")
+			if err != nil {
+				log.Fatal(err)
+			}
+			uri, missing, err := mcp.ExpandURITemplate(uriTemplate, vars)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, name := range missing {
+				log.Printf("No --var for %s, expanding it to nothing", name)
+			}
+			resourceURI = uri
+		case resourceURI == "":
+			log.Fatal("Missing --uri or --template")
+		}
+
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "resources"); err != nil {
+				return err
+			}
+			result, err := client.ReadResource(ctx, resourceURI)
+			if err != nil {
+				return err
+			}
+			if resultOutput == "json" {
+				return printJSON(result)
+			}
+			return printContents(result.Contents, outDir)
+		})
+	},
+}
+
+func init() {
+	readCmd.Flags().StringVar(&resourceURI, "uri", "", "URI of the resource")
+	readCmd.Flags().StringVar(&uriTemplate, "template", "", "URI template to expand into the URI of the resource")
+	readCmd.Flags().StringArrayVar(&templateVars, "var", nil, "Template variable as key=value (repeatable)")
+	readCmd.Flags().StringVar(&outDir, "out-dir", "", "Write each content to a file in this directory")
+	readCmd.Flags().StringVar(&resultOutput, "output", "text", "Print the result as text or as raw json")
+	rootCmd.AddCommand(readCmd)
+}
+
+// parseKeyValues splits the key=value pairs given to a repeatable flag.
+func parseKeyValues(pairs []string, flag string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s %q is not of the form key=value", flag, pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// printContents prints text contents, or writes every content to its own
+// file when dir is set.
+func printContents(contents []mcp.ResourceContents, dir string) error {
+	used := map[string]bool{}
+	for _, content := range contents {
+		mimeType := content.MimeType
+		if mimeType == "" {
+			mimeType = "unknown type"
+		}
+
+		if dir == "" {
+			if content.Blob != "" {
+				data, err := base64.StdEncoding.DecodeString(content.Blob)
+				if err != nil {
+					return fmt.Errorf("invalid blob for %s: %w", content.URI, err)
+				}
+				fmt.Printf("\033[34m%s (%s)\033[0m binary content of %d bytes, use --out-dir to save it\n", content.URI, mimeType, len(data))
+				continue
+			}
+			fmt.Printf("\033[34m%s (%s)\033[0m\n", content.URI, mimeType)
+			fmt.Println(content.Text)
+			continue
+		}
+
+		file, err := writeContent(content, dir, used)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s (%s) -> %s\n", content.URI, mimeType, file)
+	}
+	return nil
+}
+
+// writeContent saves a content to a file of its own in dir and returns
+// the file's path.
+func writeContent(content mcp.ResourceContents, dir string, used map[string]bool) (string, error) {
+	data := []byte(content.Text)
+	if content.Blob != "" {
+		var err error
+		data, err = base64.StdEncoding.DecodeString(content.Blob)
+		if err != nil {
+			return "", fmt.Errorf("invalid blob for %s: %w", content.URI, err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, contentFileName(content, used))
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// preferredExtensions picks the usual extension for types that have several.
+var preferredExtensions = map[string]string{
+	"text/plain":    ".txt",
+	"text/markdown": ".md",
+	"text/html":     ".html",
+	"image/jpeg":    ".jpg",
+}
+
+func extensionFor(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := preferredExtensions[mediaType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// hasExtension reports whether name already ends in an extension of mimeType.
+func hasExtension(name, mimeType string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	exts, _ := mime.ExtensionsByType(mediaType)
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// contentFileName names the file for a content after the last segment of
+// its URI, adding an extension for its mimeType and a counter when the
+// name was used already.
+func contentFileName(content mcp.ResourceContents, used map[string]bool) string {
+	name := ""
+	if u, err := url.Parse(content.URI); err == nil {
+		p := u.Path
+		if p == "" {
+			p = u.Opaque
+		}
+		name = path.Base(p)
+	}
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "_"), "._")
+	if name == "" {
+		name = "resource"
+	}
+
+	if ext := extensionFor(content.MimeType); ext != "" && !hasExtension(name, content.MimeType) {
+		name += ext
+	}
+
+	base, ext := strings.TrimSuffix(name, path.Ext(name)), path.Ext(name)
+	for i := 1; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	used[name] = true
+	return name
+}
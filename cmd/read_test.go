@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+func TestContentFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents []mcp.ResourceContents
+		want     []string
+	}{
+		{
+			name:     "last path segment",
+			contents: []mcp.ResourceContents{{URI: "file:///docs/report.pdf", MimeType: "application/pdf"}},
+			want:     []string{"report.pdf"},
+		},
+		{
+			name:     "extension added for the mime type",
+			contents: []mcp.ResourceContents{{URI: "file:///docs/notes", MimeType: "text/plain; charset=utf-8"}},
+			want:     []string{"notes.txt"},
+		},
+		{
+			name:     "existing extension kept regardless of case",
+			contents: []mcp.ResourceContents{{URI: "file:///photos/cat.JPEG", MimeType: "image/jpeg"}},
+			want:     []string{"cat.JPEG"},
+		},
+		{
+			name:     "no mime type",
+			contents: []mcp.ResourceContents{{URI: "db://users/42"}},
+			want:     []string{"42"},
+		},
+		{
+			name:     "opaque URI",
+			contents: []mcp.ResourceContents{{URI: "urn:isbn:0451450523"}},
+			want:     []string{"isbn_0451450523"},
+		},
+		{
+			name:     "unsafe characters replaced",
+			contents: []mcp.ResourceContents{{URI: "file:///a/my%20file%3F.txt"}},
+			want:     []string{"my_file_.txt"},
+		},
+		{
+			name:     "path traversal",
+			contents: []mcp.ResourceContents{{URI: "file:///x/..%2F..%2Fetc%2Fpasswd"}},
+			want:     []string{"passwd"},
+		},
+		{
+			name:     "hidden file",
+			contents: []mcp.ResourceContents{{URI: "file:///home/.profile"}},
+			want:     []string{"profile"},
+		},
+		{
+			name:     "nothing usable",
+			contents: []mcp.ResourceContents{{URI: "file:///"}, {URI: "::bad", MimeType: "image/png"}},
+			want:     []string{"resource", "resource.png"},
+		},
+		{
+			name: "counter for names used already",
+			contents: []mcp.ResourceContents{
+				{URI: "file:///a/notes.txt"},
+				{URI: "file:///b/notes.txt"},
+				{URI: "file:///c/notes", MimeType: "text/plain"},
+			},
+			want: []string{"notes.txt", "notes-1.txt", "notes-2.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := map[string]bool{}
+			var got []string
+			for _, content := range tt.contents {
+				got = append(got, contentFileName(content, used))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var resourceTemplatesCmd = &cobra.Command{
+	Use:   "resource-templates",
+	Short: "List the resource templates of the server",
+	Long: `Send resources/templates/list and print the parameterized resources of
+the server. Read one of them with mcpt read --template.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runRequest(cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "resources"); err != nil {
+				return err
+			}
+			templates, next, err := client.ListResourceTemplatesPages(ctx, pageOptions())
+			if err != nil {
+				return err
+			}
+			defer reportNextCursor(next)
+			return display(client, templates, output)
+		})
+	},
+}
+
+func init() {
+	listCmd.AddCommand(resourceTemplatesCmd)
+}
@@ -4,8 +4,19 @@ Copyright © 2025 33arc
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/33arc/mcpt/mcp"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +24,10 @@ var host string
 var output string
 var protocolVersion string
 var sseEnabled bool
+var serverCommand string
+var transportName string
+var sessionID string
+var unixSocket string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -38,12 +53,333 @@ func Execute() {
 	}
 }
 
+// requestTimeout bounds the requests of the commands that do not run until
+// interrupted. Starting the server and initializing the session do not
+// count, as launching a stdio server with npx or uvx can take a while.
+const requestTimeout = 5 * time.Second
+
+// Exit statuses of mcpt besides 0.
+const (
+	exitFailure        = 1 // the command could not be carried out
+	exitToolError      = 2 // the tool ran but reported an error
+	exitSchemaMismatch = 3 // the tool's structuredContent does not match its outputSchema
+)
+
+// exitStatus ends mcpt with a given status, once whatever it wanted to
+// say has already been printed.
+type exitStatus struct {
+	code int
+}
+
+func (e *exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestTimeout)
+}
+
+// interruptContext is cancelled by the first Ctrl-C or SIGTERM, with a
+// cause that says so. A second Ctrl-C kills mcpt right away.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signals:
+			if sig == os.Interrupt {
+				cancel(errors.New("interrupted by user"))
+			} else {
+				cancel(errors.New("terminated"))
+			}
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// runSession connects to the server, opens or attaches to a session, runs
+// fn and closes the client again, then exits if anything went wrong.
+func runSession(ctx context.Context, cmd *cobra.Command, args []string, fn func(context.Context, *mcp.Client) error) {
+	if err := checkLogLevel(logLevel); err != nil {
+		log.Fatal(err)
+	}
+	client, err := newClient(cmd, args)
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+
+	err = startSession(ctx, client)
+	if err == nil {
+		stopRoots := watchRoots(ctx, client)
+		err = fn(ctx, client)
+		stopRoots()
+	}
+	if cerr := client.Close(); cerr != nil {
+		log.Printf("Failed to close session: %v", cerr)
+	}
+	exitOnError(err)
+}
+
+// runRequest is runSession for the commands that send their requests and
+// are done: fn gets requestTimeout to finish, from the moment the session
+// is ready.
+func runRequest(cmd *cobra.Command, args []string, fn func(context.Context, *mcp.Client) error) {
+	ctx, cancel := interruptContext()
+	defer cancel()
+	runSession(ctx, cmd, args, func(ctx context.Context, client *mcp.Client) error {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		return fn(ctx, client)
+	})
+}
+
+func exitOnError(err error) {
+	if err == nil {
+		return
+	}
+	var status *exitStatus
+	if errors.As(err, &status) {
+		os.Exit(status.code)
+	}
+	log.Println(err)
+	os.Exit(exitFailure)
+}
+
+// startSession initializes a new session, unless the client was attached
+// to an existing one.
+func startSession(ctx context.Context, client *mcp.Client) error {
+	if client.SID != "" {
+		// what the attached session supports is unknown, so just try
+		if logLevel != "" {
+			if err := client.SetLogLevel(ctx, logLevel); err != nil {
+				log.Printf("Failed to set log level: %v", err)
+			}
+		}
+		return nil
+	}
+
+	result, err := client.Initialize(ctx)
+	if err != nil {
+		return err
+	}
+	if transportName == mcp.TransportAuto && !sseEnabled {
+		log.Printf("Detected %s transport", client.TransportName)
+	}
+	if result.ProtocolVersion != client.ProtocolVersion {
+		log.Printf("Warning: requested protocol version %s, but the server speaks %s", client.ProtocolVersion, result.ProtocolVersion)
+	}
+	if logLevel != "" {
+		if err := requireCapability(client, "logging"); err != nil {
+			log.Printf("%v, ignoring --log-level", err)
+		} else if err := client.SetLogLevel(ctx, logLevel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newClient connects to the server named on the command line and, over
+// Streamable HTTP, attaches to the session given with --session-id or saved
+// by "mcpt session start". The other transports have no session ids to
+// attach to, so --session-id is an error for them and saved sessions are
+// left alone.
+func newClient(cmd *cobra.Command, args []string) (*mcp.Client, error) {
+	client, err := dialClient(cmd, args)
+	if err != nil {
+		return nil, err
+	}
+	if client.TransportName != mcp.TransportStreamableHTTP {
+		if sessionID != "" {
+			client.Close()
+			return nil, fmt.Errorf("--session-id needs the %s transport, not %s", mcp.TransportStreamableHTTP, client.TransportName)
+		}
+		return client, nil
+	}
+
+	sid := sessionID
+	if sid == "" {
+		sid = savedSession(host)
+		if sid != "" {
+			log.Printf("Attaching to saved session %s", sid)
+		}
+	}
+	if sid != "" {
+		client.SID = sid
+		client.KeepSession = true
+	}
+	return client, nil
+}
+
+// dialClient connects to the server named on the command line: a stdio server
+// given after "--" or with --command, otherwise the HTTP server at --host.
+func dialClient(cmd *cobra.Command, args []string) (*mcp.Client, error) {
+	sampling, err := newSamplingHandler()
+	if err != nil {
+		return nil, err
+	}
+	elicitation, err := newElicitationHandler()
+	if err != nil {
+		return nil, err
+	}
+	roots, err := loadRoots()
+	if err != nil {
+		return nil, err
+	}
+
+	var command []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		command = args[dash:]
+	} else if serverCommand != "" {
+		if command, err = splitCommand(serverCommand); err != nil {
+			return nil, fmt.Errorf("invalid --command: %w", err)
+		}
+	}
+
+	var client *mcp.Client
+	if len(command) > 0 {
+		client, err = mcp.NewStdioClient(command, &prefixWriter{w: os.Stderr, prefix: "[server] "}, protocolVersion)
+	} else {
+		transport := transportName
+		if sseEnabled {
+			transport = mcp.TransportSSE
+		}
+		client, err = mcp.NewClient(host, transport, protocolVersion)
+		if err == nil && unixSocket != "" {
+			client.UseUnixSocket(unixSocket)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client.Logf = log.Printf
+	client.Sampling = sampling
+	client.Elicitation = elicitation
+	client.Roots = roots
+	client.OnNotification = func(msg *mcp.JSONRPCMessage) {
+		if logMsg, ok := mcp.ParseLogMessage(msg); ok {
+			printLogMessage(logMsg)
+			return
+		}
+		log.Printf("Notification %s: %s", msg.Method, bytes.TrimSpace(msg.Params))
+	}
+	return client, nil
+}
+
+// prefixWriter copies whole lines to w, each one prefixed so that server
+// output stays apart from ours.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.buf[:i]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes out a last line that was not terminated by a newline.
+func (p *prefixWriter) Flush() error {
+	if len(p.buf) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.buf)
+	p.buf = nil
+	return err
+}
+
+// splitCommand splits a command line into words the way a POSIX shell
+// does, minus expansions: words are separated by blanks, single quotes keep
+// everything literally, and in double quotes a backslash only escapes
+// $, `, ", \ and newlines.
+func splitCommand(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]):
+				i++
+				word.WriteRune(runes[i])
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
 func init() {
-	rootCmd.PersistentFlags().BoolVar(&sseEnabled, "sse", false, "enable SSE")
+	rootCmd.PersistentFlags().StringVar(&transportName, "transport", mcp.TransportStreamableHTTP, "HTTP transport: streamable-http, sse (protocol 2024-11-05) or auto")
+	rootCmd.PersistentFlags().BoolVar(&sseEnabled, "sse", false, "Shorthand for --transport sse")
 	rootCmd.PersistentFlags().StringVar(&output, "output", "json", "Which output to use")
-	rootCmd.PersistentFlags().StringVar(&host, "host", "http://localhost:8080/mcp", "MCP server URL")
+	rootCmd.PersistentFlags().StringVar(&host, "host", "http://localhost:8080/mcp", "MCP server URL (http, https, ws, wss, or unix:///path/to.sock:/mcp)")
+	rootCmd.PersistentFlags().StringVar(&unixSocket, "unix-socket", "", "Connect to --host over this Unix domain socket")
 	rootCmd.PersistentFlags().StringVar(&protocolVersion, "protocol-version", "2025-06-18", "MCP protocol version")
-	rootCmd.MarkPersistentFlagRequired("host")
+	rootCmd.PersistentFlags().StringVar(&sessionID, "session-id", "", "Attach to an existing session instead of initializing a new one")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Ask the server for log messages of this level and above (debug, info, notice, warning, error, ...)")
+	rootCmd.PersistentFlags().StringVar(&samplingRules, "sampling-rules", "", "Answer sampling requests from the rules in this YAML file")
+	rootCmd.PersistentFlags().StringVar(&samplingCommand, "sampling-command", "", "Answer sampling requests by running this shell command, which gets the request on stdin")
+	rootCmd.PersistentFlags().BoolVar(&samplingInteractive, "sampling-interactive", false, "Answer sampling requests by asking on the terminal")
+	rootCmd.PersistentFlags().StringVar(&elicitAnswers, "elicit-answers", "", "Answer elicitation requests from this JSON or YAML file of field values")
+	rootCmd.PersistentFlags().StringArrayVar(&elicitValues, "elicit", nil, "Answer elicitation requests with this field as name=value (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&elicitAction, "elicit-action", "", "Answer elicitation requests with accept, decline or cancel instead of asking on the terminal")
+	rootCmd.PersistentFlags().StringArrayVar(&rootFlags, "root", nil, "Offer the server this root, as file:///path[=name] (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&rootsFile, "roots-file", "", "Offer the server the roots in this file, one file:///path[=name] per line; SIGHUP reloads it")
+	rootCmd.PersistentFlags().StringVar(&serverCommand, "command", "", "Launch a stdio MCP server with this command line, quoted as in a shell, instead of using --host")
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
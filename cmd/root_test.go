@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr string
+	}{
+		{line: "python3 server.py", want: []string{"python3", "server.py"}},
+		{line: "  npx   -y\t@scope/server  ", want: []string{"npx", "-y", "@scope/server"}},
+		{line: "python3 'my server.py'", want: []string{"python3", "my server.py"}},
+		{line: `python3 "my server.py" --name "a \"b\""`, want: []string{"python3", "my server.py", "--name", `a "b"`}},
+		{line: `echo 'a\b' "c\d" e\ f`, want: []string{"echo", `a\b`, `c\d`, "e f"}},
+		{line: `run --x='' ""`, want: []string{"run", "--x=", ""}},
+		{line: `a"b"'c'd`, want: []string{"abcd"}},
+		{line: "", want: nil},
+		{line: "python3 'server.py", wantErr: "unterminated ' quote"},
+		{line: `python3 "server.py`, wantErr: `unterminated " quote`},
+		{line: `python3 server.py\`, wantErr: "trailing backslash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := splitCommand(tt.line)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitCommand failed: %v", err)
+			}
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
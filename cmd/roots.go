@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+var rootFlags []string
+var rootsFile string
+
+// loadRoots collects the roots given with --root and in --roots-file, or
+// returns nil when there are none and roots are not advertised.
+func loadRoots() ([]mcp.Root, error) {
+	specs := rootFlags
+	if rootsFile != "" {
+		data, err := os.ReadFile(rootsFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				specs = append(specs, line)
+			}
+		}
+	}
+	if len(specs) == 0 && rootsFile == "" {
+		return nil, nil
+	}
+
+	roots := []mcp.Root{}
+	for _, spec := range specs {
+		root, err := parseRoot(spec)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// parseRoot reads a root given as file:///path[=name]. A plain path is
+// turned into a file URI.
+func parseRoot(spec string) (mcp.Root, error) {
+	uri, name, _ := strings.Cut(spec, "=")
+	if !strings.Contains(uri, "://") {
+		path, err := filepath.Abs(uri)
+		if err != nil {
+			return mcp.Root{}, err
+		}
+		uri = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+	if u, err := url.Parse(uri); err != nil || u.Scheme != "file" {
+		return mcp.Root{}, fmt.Errorf("root %q is not a file:// URI", spec)
+	}
+	return mcp.Root{URI: uri, Name: name}, nil
+}
+
+// watchRoots reloads the roots whenever mcpt gets SIGHUP and sends
+// notifications/roots/list_changed, until the returned function is called.
+func watchRoots(ctx context.Context, client *mcp.Client) (stop func()) {
+	if client.Roots == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangups)
+		for {
+			select {
+			case <-hangups:
+			case <-ctx.Done():
+				return
+			}
+
+			roots, err := loadRoots()
+			if err != nil {
+				log.Printf("Failed to reload roots: %v", err)
+				continue
+			}
+			notifyCtx, cancelNotify := context.WithTimeout(ctx, requestTimeout)
+			err = client.SetRoots(notifyCtx, roots)
+			cancelNotify()
+			if err != nil {
+				log.Printf("Failed to announce roots: %v", err)
+				continue
+			}
+			log.Printf("Sent roots/list_changed for %d roots", len(roots))
+		}
+	}()
+	return cancel
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/33arc/mcpt/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+var samplingRules string
+var samplingCommand string
+var samplingInteractive bool
+
+// samplingRule answers the sampling requests whose messages match Match,
+// a regular expression; a rule without Match answers every request.
+type samplingRule struct {
+	Match      string `yaml:"match"`
+	Reply      string `yaml:"reply"`
+	Reject     bool   `yaml:"reject"`
+	Model      string `yaml:"model"`
+	StopReason string `yaml:"stopReason"`
+
+	pattern *regexp.Regexp
+}
+
+// newSamplingHandler returns the sampling responder picked on the command
+// line, or nil when there is none and sampling is not advertised.
+func newSamplingHandler() (mcp.SamplingHandler, error) {
+	picked := 0
+	for _, set := range []bool{samplingRules != "", samplingCommand != "", samplingInteractive} {
+		if set {
+			picked++
+		}
+	}
+	if picked > 1 {
+		return nil, errors.New("use only one of --sampling-rules, --sampling-command and --sampling-interactive")
+	}
+
+	switch {
+	case samplingRules != "":
+		rules, err := loadSamplingRules(samplingRules)
+		if err != nil {
+			return nil, err
+		}
+		return rulesSampler(rules), nil
+	case samplingCommand != "":
+		return commandSampler(samplingCommand), nil
+	case samplingInteractive:
+		return interactiveSampler, nil
+	}
+	return nil, nil
+}
+
+func loadSamplingRules(file string) ([]samplingRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Rules []samplingRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid sampling rules %s: %w", file, err)
+	}
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+		if rule.pattern, err = regexp.Compile(rule.Match); err != nil {
+			return nil, fmt.Errorf("invalid match of sampling rule %d: %w", i+1, err)
+		}
+	}
+	return config.Rules, nil
+}
+
+// samplingText joins the text of all messages of a request, which is
+// what the rules are matched against.
+func samplingText(req *mcp.CreateMessageRequest) string {
+	var texts []string
+	for _, message := range req.Messages {
+		if message.Content.Type == "text" {
+			texts = append(texts, message.Content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// rulesSampler answers with the reply of the first rule that matches.
+func rulesSampler(rules []samplingRule) mcp.SamplingHandler {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		text := samplingText(req)
+		for _, rule := range rules {
+			if !rule.pattern.MatchString(text) {
+				continue
+			}
+			if rule.Reject {
+				return nil, &mcp.JSONRPCError{Code: mcp.CodeUserRejected, Message: "Sampling request rejected by rule"}
+			}
+			model, stopReason := rule.Model, rule.StopReason
+			if model == "" {
+				model = "mcpt-rules"
+			}
+			if stopReason == "" {
+				stopReason = "endTurn"
+			}
+			return &mcp.CreateMessageResult{
+				Content:    mcp.Content{Type: "text", Text: rule.Reply},
+				Model:      model,
+				StopReason: stopReason,
+			}, nil
+		}
+		return nil, &mcp.JSONRPCError{Code: mcp.CodeUserRejected, Message: "No sampling rule matches the request"}
+	}
+}
+
+// commandSampler runs command with the request as JSON on its stdin. It
+// may print a complete result as JSON, or just the text of the reply.
+func commandSampler(command string) mcp.SamplingHandler {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		reqJSON, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+
+		samplingCmd := exec.CommandContext(ctx, "sh", "-c", command)
+		samplingCmd.Stdin = bytes.NewReader(reqJSON)
+		samplingCmd.Stderr = os.Stderr
+		out, err := samplingCmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("sampling command failed: %w", err)
+		}
+
+		var result mcp.CreateMessageResult
+		if json.Unmarshal(out, &result) == nil && result.Content.Type != "" {
+			if result.Model == "" {
+				result.Model = "mcpt-command"
+			}
+			return &result, nil
+		}
+		return &mcp.CreateMessageResult{
+			Content:    mcp.Content{Type: "text", Text: strings.TrimRight(string(out), "\n")},
+			Model:      "mcpt-command",
+			StopReason: "endTurn",
+		}, nil
+	}
+}
+
+// interactiveSampler shows the request to the operator and lets them type
+// the reply. An empty reply declines the request.
+func interactiveSampler(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	fmt.Fprintf(os.Stderr, "\033[34mThe server asks for a message (maxTokens %d)\033[0m\n", req.MaxTokens)
+	if req.SystemPrompt != "" {
+		fmt.Fprintf(os.Stderr, "\033[34m[system]\033[0m %s\n", req.SystemPrompt)
+	}
+	for _, message := range req.Messages {
+		text := message.Content.Text
+		if message.Content.Type != "text" {
+			text = fmt.Sprintf("[%s %s]", message.Content.Type, message.Content.MimeType)
+		}
+		fmt.Fprintf(os.Stderr, "\033[34m[%s]\033[0m %s\n", message.Role, text)
+	}
+
+	var lines []string
+	question := "Reply, ending with an empty line (an empty reply declines):"
+	for {
+		line, err := ask(question)
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if line == "" || err != nil {
+			break
+		}
+		question = ">"
+	}
+	if len(lines) == 0 {
+		return nil, &mcp.JSONRPCError{Code: mcp.CodeUserRejected, Message: "User rejected sampling request"}
+	}
+	return &mcp.CreateMessageResult{
+		Content:    mcp.Content{Type: "text", Text: strings.Join(lines, "\n")},
+		Model:      "human",
+		StopReason: "endTurn",
+	}, nil
+}
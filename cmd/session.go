@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Keep one session open across several runs",
+	Long: `Start a session, keep its id on disk and end it again.
+
+While a session is saved for a host, every other command sent to that host
+attaches to it instead of initializing a new one, so a chain of calls runs
+against the same stateful session.`,
+}
+
+var sessionStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Initialize a session and save its id",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := interruptContext()
+		defer cancel()
+
+		client, err := dialClient(cmd, args)
+		if err != nil {
+			log.Fatalf("Failed to connect: %v", err)
+		}
+		client.KeepSession = true
+		_, err = client.Initialize(ctx)
+		client.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if client.SID == "" {
+			log.Fatal("Server did not assign a session id")
+		}
+
+		if err := saveSession(host, client.SID); err != nil {
+			log.Fatalf("Failed to save session: %v", err)
+		}
+		fmt.Println(client.SID)
+	},
+}
+
+var sessionEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "Terminate the saved session",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := newClient(cmd, args)
+		if err != nil {
+			log.Fatalf("Failed to connect: %v", err)
+		}
+		if client.SID == "" {
+			log.Fatalf("No saved session for %s", host)
+		}
+		sid := client.SID
+		client.KeepSession = false
+		if err := client.Close(); err != nil {
+			log.Fatal(err)
+		}
+		// --session-id may name another session than the saved one
+		if savedSession(host) == sid {
+			if err := saveSession(host, ""); err != nil {
+				log.Fatalf("Failed to forget session: %v", err)
+			}
+		}
+	},
+}
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the saved session id",
+	Run: func(cmd *cobra.Command, args []string) {
+		sid := savedSession(host)
+		if sid == "" {
+			log.Fatalf("No saved session for %s", host)
+		}
+		fmt.Println(sid)
+	},
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionStartCmd)
+	sessionCmd.AddCommand(sessionEndCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
+
+// sessionsFile holds the saved session ids, keyed by server URL.
+func sessionsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcpt", "sessions.json"), nil
+}
+
+func loadSessions() (map[string]string, error) {
+	sessions := map[string]string{}
+	path, err := sessionsFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sessions, nil
+}
+
+func savedSession(host string) string {
+	sessions, err := loadSessions()
+	if err != nil {
+		log.Printf("Ignoring saved sessions: %v", err)
+		return ""
+	}
+	return sessions[host]
+}
+
+// saveSession records sid for host, or forgets the session when sid is empty.
+func saveSession(host, sid string) error {
+	sessions, err := loadSessions()
+	if err != nil {
+		return err
+	}
+	if sid == "" {
+		delete(sessions, host)
+	} else {
+		sessions[host] = sid
+	}
+
+	path, err := sessionsFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
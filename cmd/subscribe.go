@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var showDiff bool
+var execHook string
+
+var subscribeCmd = &cobra.Command{
+	Use:   "subscribe",
+	Short: "Follow the changes of a resource",
+	Long: `Send resources/subscribe for --uri and keep the session open. Every
+notifications/resources/updated for it re-reads the resource and prints the
+new content, or with --diff the lines that changed. On exit the resource is
+unsubscribed again.
+
+With --exec, a shell command runs after every update, with the new text
+content on its stdin and the URI in $MCPT_URI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if resourceURI == "" {
+			log.Fatal("Missing --uri")
+		}
+
+		ctx, stop := interruptContext()
+		defer stop()
+
+		runSession(ctx, cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, "resources.subscribe"); err != nil {
+				return err
+			}
+			previous, err := readText(ctx, client, resourceURI)
+			if err != nil {
+				return err
+			}
+			fmt.Println(previous)
+
+			if err := client.Subscribe(ctx, resourceURI); err != nil {
+				return err
+			}
+			defer func() {
+				unsubscribeCtx, cancel := requestContext()
+				defer cancel()
+				if err := client.Unsubscribe(unsubscribeCtx, resourceURI); err != nil {
+					log.Printf("Failed to unsubscribe: %v", err)
+				}
+			}()
+
+			isUpdate := func(msg *mcp.JSONRPCMessage) bool {
+				uri, ok := mcp.UpdatedResource(msg)
+				return ok && uri == resourceURI
+			}
+			err = followChanges(ctx, client, isUpdate, func() {
+				current, err := readText(ctx, client, resourceURI)
+				if err != nil {
+					log.Printf("Failed to read %s: %v", resourceURI, err)
+					return
+				}
+				fmt.Printf("\033[34m%s updated %s\033[0m\n", time.Now().Format("15:04:05.000"), resourceURI)
+				if showDiff {
+					printLineDiff(previous, current)
+				} else {
+					fmt.Println(current)
+				}
+				previous = current
+
+				if execHook != "" {
+					runHook(ctx, execHook, resourceURI, current)
+				}
+			})
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		})
+	},
+}
+
+func init() {
+	subscribeCmd.Flags().StringVar(&resourceURI, "uri", "", "URI of the resource")
+	subscribeCmd.Flags().BoolVar(&showDiff, "diff", false, "Print only the lines that changed")
+	subscribeCmd.Flags().StringVar(&execHook, "exec", "", "Shell command to run on every update")
+	rootCmd.AddCommand(subscribeCmd)
+}
+
+// followChanges calls refresh for every message of the server that matches
+// until ctx is done. Messages that arrive while refresh is waiting for its
+// own requests are passed to OnNotification rather than to Listen, so the
+// matching ones are caught there and lead to another refresh once the
+// current one is done.
+func followChanges(ctx context.Context, client *mcp.Client, match func(*mcp.JSONRPCMessage) bool, refresh func()) error {
+	var mu sync.Mutex
+	pending := false
+
+	onNotification := client.OnNotification
+	client.OnNotification = func(msg *mcp.JSONRPCMessage) {
+		if !match(msg) {
+			if onNotification != nil {
+				onNotification(msg)
+			}
+			return
+		}
+		mu.Lock()
+		pending = true
+		mu.Unlock()
+	}
+	defer func() { client.OnNotification = onNotification }()
+
+	return client.Listen(ctx, func(msg *mcp.JSONRPCMessage) {
+		if !match(msg) {
+			return
+		}
+		for again := true; again && ctx.Err() == nil; {
+			refresh()
+			mu.Lock()
+			again, pending = pending, false
+			mu.Unlock()
+		}
+	})
+}
+
+// readText reads a resource and renders its contents as one text, with a
+// placeholder for every binary content.
+func readText(ctx context.Context, client *mcp.Client, uri string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	result, err := client.ReadResource(ctx, uri)
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	for _, content := range result.Contents {
+		if content.Blob != "" {
+			size := base64.StdEncoding.DecodedLen(len(content.Blob))
+			parts = append(parts, fmt.Sprintf("<%s binary content, about %d bytes>", content.MimeType, size))
+			continue
+		}
+		parts = append(parts, content.Text)
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+func runHook(ctx context.Context, hook, uri, content string) {
+	hookCmd := exec.CommandContext(ctx, "sh", "-c", hook)
+	hookCmd.Env = append(os.Environ(), "MCPT_URI="+uri)
+	hookCmd.Stdin = strings.NewReader(content)
+	hookCmd.Stdout = os.Stdout
+	hookCmd.Stderr = os.Stderr
+	if err := hookCmd.Run(); err != nil {
+		log.Printf("--exec hook failed: %v", err)
+	}
+}
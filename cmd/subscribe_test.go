@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/33arc/mcpt/mcp"
+)
+
+// updateTransport sends the listener one notification per entry of
+// listen, and with the response to the n-th request first sends the
+// notifications of during[n].
+type updateTransport struct {
+	listen   []string
+	during   map[int][]string
+	requests int
+}
+
+func notification(method string) *mcp.JSONRPCMessage {
+	return &mcp.JSONRPCMessage{JSONRPC: "2.0", Method: method, Params: json.RawMessage(`{"uri":"file:///a"}`)}
+}
+
+func (u *updateTransport) RoundTrip(ctx context.Context, req *mcp.JSONRPCMessage, handle func(*mcp.JSONRPCMessage)) (*mcp.JSONRPCMessage, error) {
+	u.requests++
+	for _, method := range u.during[u.requests] {
+		handle(notification(method))
+	}
+	result := fmt.Sprintf(`{"contents":[{"uri":"file:///a","text":"v%d"}]}`, u.requests)
+	return &mcp.JSONRPCMessage{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(result)}, nil
+}
+
+func (u *updateTransport) Send(ctx context.Context, msg *mcp.JSONRPCMessage) error { return nil }
+
+func (u *updateTransport) Listen(ctx context.Context, handle func(*mcp.JSONRPCMessage)) error {
+	for _, method := range u.listen {
+		handle(notification(method))
+	}
+	return nil
+}
+
+func (u *updateTransport) Close() error { return nil }
+
+func TestFollowChanges(t *testing.T) {
+	const updated = "notifications/resources/updated"
+	const other = "notifications/message"
+
+	tests := []struct {
+		name       string
+		listen     []string
+		during     map[int][]string
+		wantReads  []string
+		wantOthers int
+	}{
+		{
+			name:      "one read per update",
+			listen:    []string{updated, updated},
+			wantReads: []string{"v1", "v2"},
+		},
+		{
+			name:      "update during the read",
+			listen:    []string{updated},
+			during:    map[int][]string{1: {updated}},
+			wantReads: []string{"v1", "v2"},
+		},
+		{
+			name:      "several updates during the read are one more read",
+			listen:    []string{updated},
+			during:    map[int][]string{1: {updated, updated}, 2: {updated}},
+			wantReads: []string{"v1", "v2", "v3"},
+		},
+		{
+			name:       "other notifications are passed on",
+			listen:     []string{other, updated},
+			during:     map[int][]string{1: {other}},
+			wantReads:  []string{"v1"},
+			wantOthers: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			others := 0
+			client := &mcp.Client{
+				Transport:      &updateTransport{listen: tt.listen, during: tt.during},
+				OnNotification: func(*mcp.JSONRPCMessage) { others++ },
+			}
+			isUpdate := func(msg *mcp.JSONRPCMessage) bool {
+				uri, ok := mcp.UpdatedResource(msg)
+				return ok && uri == "file:///a"
+			}
+
+			var reads []string
+			err := followChanges(context.Background(), client, isUpdate, func() {
+				result, err := client.ReadResource(context.Background(), "file:///a")
+				if err != nil {
+					t.Fatalf("ReadResource failed: %v", err)
+				}
+				reads = append(reads, result.Contents[0].Text)
+			})
+			if err != nil {
+				t.Fatalf("followChanges failed: %v", err)
+			}
+			if fmt.Sprint(reads) != fmt.Sprint(tt.wantReads) {
+				t.Errorf("reads = %v, want %v", reads, tt.wantReads)
+			}
+			if others != tt.wantOthers {
+				t.Errorf("%d notifications passed on, want %d", others, tt.wantOthers)
+			}
+			if client.OnNotification == nil {
+				t.Error("OnNotification was not restored")
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/33arc/mcpt/mcp"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch tools|prompts|resources",
+	Short: "Print how a list of the server changes during a session",
+	Long: `Keep the session open and list the tools, prompts or resources of the
+server again whenever it sends the matching notifications/*/list_changed.
+Each time, print with a timestamp what was added, removed or changed, down
+to the fields (description, inputSchema, ...) of every changed item.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.ArgsLenAtDash() == 0 || len(args) == 0 {
+			log.Fatal("Missing what to watch: tools, prompts or resources")
+		}
+		kind := args[0]
+		if _, ok := watchKeys[kind]; !ok {
+			log.Fatalf("Cannot watch %q, use tools, prompts or resources", kind)
+		}
+
+		ctx, stop := interruptContext()
+		defer stop()
+
+		runSession(ctx, cmd, args, func(ctx context.Context, client *mcp.Client) error {
+			if err := requireCapability(client, kind); err != nil {
+				return err
+			}
+			previous, err := listItems(ctx, client, kind)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s \033[34mwatching %d %s\033[0m\n", time.Now().Format("15:04:05.000"), len(previous), kind)
+
+			isChange := func(msg *mcp.JSONRPCMessage) bool {
+				return msg.Method == "notifications/"+kind+"/list_changed"
+			}
+			err = followChanges(ctx, client, isChange, func() {
+				current, err := listItems(ctx, client, kind)
+				if err != nil {
+					log.Printf("Failed to list %s: %v", kind, err)
+					return
+				}
+				fmt.Printf("%s \033[34m%s/list_changed\033[0m\n", time.Now().Format("15:04:05.000"), kind)
+				printItemsDiff(kind, previous, current)
+				previous = current
+			})
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchKeys names the field that identifies the items of each list.
+var watchKeys = map[string]string{
+	"tools":     "name",
+	"prompts":   "name",
+	"resources": "uri",
+}
+
+// listItems lists kind and returns its items as generic JSON objects,
+// keyed by what identifies them.
+func listItems(ctx context.Context, client *mcp.Client, kind string) (map[string]map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var list interface{}
+	var err error
+	switch kind {
+	case "tools":
+		list, err = client.ListTools(ctx)
+	case "prompts":
+		list, err = client.ListPrompts(ctx)
+	case "resources":
+		list, err = client.ListResources(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+	items := make(map[string]map[string]interface{}, len(objects))
+	for _, object := range objects {
+		items[fmt.Sprint(object[watchKeys[kind]])] = object
+	}
+	return items, nil
+}
+
+// printItemsDiff prints the items added, removed and changed between two
+// listings. Changed text fields are shown as old -> new, changed objects
+// such as inputSchema as a diff of their JSON.
+func printItemsDiff(kind string, previous, current map[string]map[string]interface{}) {
+	noun := strings.TrimSuffix(kind, "s")
+	changes := 0
+	for _, key := range sortedKeys(previous, current) {
+		before, hadBefore := previous[key]
+		after, hasAfter := current[key]
+		switch {
+		case !hadBefore:
+			fmt.Printf("\033[32m+ %s %s\033[0m%s\n", noun, key, describeItem(after))
+			changes++
+		case !hasAfter:
+			fmt.Printf("\033[31m- %s %s\033[0m\n", noun, key)
+			changes++
+		case !reflect.DeepEqual(before, after):
+			fmt.Printf("\033[33m~ %s %s\033[0m\n", noun, key)
+			for _, field := range sortedKeys(before, after) {
+				if reflect.DeepEqual(before[field], after[field]) {
+					continue
+				}
+				printFieldChange(field, before[field], after[field])
+			}
+			changes++
+		}
+	}
+	if changes == 0 {
+		fmt.Println("no changes")
+	}
+}
+
+func describeItem(item map[string]interface{}) string {
+	if description, ok := item["description"].(string); ok && description != "" {
+		return ": " + description
+	}
+	return ""
+}
+
+func printFieldChange(field string, before, after interface{}) {
+	_, beforeIsObject := before.(map[string]interface{})
+	_, afterIsObject := after.(map[string]interface{})
+	if !beforeIsObject || !afterIsObject {
+		fmt.Printf("    %s: %s -> %s\n", field, jsonValue(before), jsonValue(after))
+		return
+	}
+	fmt.Printf("    %s:\n", field)
+	beforeJSON, _ := json.MarshalIndent(before, "      ", "  ")
+	afterJSON, _ := json.MarshalIndent(after, "      ", "  ")
+	printLineDiff("      "+string(beforeJSON), "      "+string(afterJSON))
+}
+
+// jsonValue shows a field value, or "(none)" for a field that is missing.
+func jsonValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
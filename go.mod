@@ -4,10 +4,15 @@ go 1.23.0
 
 toolchain go1.24.6
 
+require (
+	github.com/google/jsonschema-go v0.4.2
+	github.com/gorilla/websocket v1.5.3
+	github.com/spf13/cobra v1.9.1
+	github.com/yosida95/uritemplate/v3 v3.0.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/modelcontextprotocol/go-sdk v0.2.0 // indirect
-	github.com/spf13/cobra v1.9.1 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 )
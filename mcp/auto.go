@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+)
+
+// autoTransport picks the transport on the first request, following the
+// backwards compatibility rules of the Streamable HTTP transport: POST the
+// request as Streamable HTTP and, if that fails with a 4xx status, fall
+// back to the legacy HTTP+SSE transport at the same URL.
+type autoTransport struct {
+	c      *Client
+	picked Transport
+}
+
+func (t *autoTransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	if t.picked != nil {
+		return t.picked.RoundTrip(ctx, req, handle)
+	}
+
+	streamable := &httpTransport{c: t.c}
+	resp, err := streamable.RoundTrip(ctx, req, handle)
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode/100 == 4 {
+		legacy := &legacySSETransport{c: t.c}
+		resp, err = legacy.RoundTrip(ctx, req, handle)
+		if err != nil {
+			return nil, err
+		}
+		t.pick(legacy, TransportSSE)
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.pick(streamable, TransportStreamableHTTP)
+	return resp, nil
+}
+
+func (t *autoTransport) pick(transport Transport, name string) {
+	t.picked = transport
+	t.c.TransportName = name
+}
+
+func (t *autoTransport) Send(ctx context.Context, msg *JSONRPCMessage) error {
+	if t.picked == nil {
+		return errors.New("transport not detected yet: the first message must be a request")
+	}
+	return t.picked.Send(ctx, msg)
+}
+
+func (t *autoTransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	if t.picked == nil {
+		return errors.New("transport not detected yet: the session must be initialized first")
+	}
+	return t.picked.Listen(ctx, handle)
+}
+
+func (t *autoTransport) Close() error {
+	if t.picked == nil {
+		return nil
+	}
+	return t.picked.Close()
+}
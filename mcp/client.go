@@ -1,462 +1,420 @@
 package mcp
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Client talks to one MCP server. Its methods return the server's results
+// and never print anything; set OnNotification and Logf to see what
+// happens along the way.
 type Client struct {
 	Host            string
-	SSE             bool
+	Command         []string
+	TransportName   string
 	SID             string
-	CTX             context.Context
-	Cancel          context.CancelFunc
+	KeepSession     bool
 	HTTPClient      *http.Client
+	UnixSocket      string
+	Transport       Transport
 	ProtocolVersion string
+	// Server is what the server answered to Initialize. It stays nil for
+	// clients attached to an existing session.
+	Server *InitializeResult
+	// OnNotification, when set, is called with every notification the
+	// server sends while a request is in flight.
+	OnNotification func(*JSONRPCMessage)
+	// Sampling, when set, answers the sampling/createMessage requests of
+	// the server, and the sampling capability is advertised for it.
+	Sampling SamplingHandler
+	// Elicitation, when set, answers the elicitation/create requests of
+	// the server, and the elicitation capability is advertised for it.
+	Elicitation ElicitationHandler
+	// Roots, when not nil, are offered to the server in answer to
+	// roots/list, and the roots capability is advertised. Use SetRoots to
+	// change them during a session.
+	Roots   []Root
+	rootsMu sync.Mutex
+	// Logf, when set, receives diagnostics such as reconnections or
+	// malformed messages that were skipped.
+	Logf     func(format string, v ...interface{})
+	sidMu    sync.Mutex // guards SID once requests may run concurrently
+	nextID   atomic.Int64
+	progress sync.Map // progress token -> func(*Progress)
 }
 
-type JSONRPCRequest struct {
-	JSONRPC string      `json:"jsonrpc"`
-	ID      int         `json:"id"`
-	Method  string      `json:"method"`
-	Params  interface{} `json:"params"`
-}
+// cancelTimeout bounds the notification that cancels a request, which is
+// sent after the request's own context is done.
+const cancelTimeout = 2 * time.Second
+
+// Transport names accepted by NewClient.
+const (
+	TransportStreamableHTTP = "streamable-http"
+	TransportSSE            = "sse"
+	TransportAuto           = "auto"
+	TransportStdio          = "stdio"
+	TransportWebSocket      = "websocket"
+)
 
-func NewClient(host string, sseEnabled bool, protocolVersion string) *Client {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// NewClient connects to the server at host over the named transport. With
+// TransportAuto the transport is detected when the session is initialized.
+// A ws:// or wss:// host always uses the WebSocket transport, and a host such
+// as unix:///run/mcp.sock:/mcp reaches the server over a Unix domain socket.
+func NewClient(host string, transport string, protocolVersion string) (*Client, error) {
 	httpClient := &http.Client{}
 
 	if protocolVersion == "" {
 		protocolVersion = "2025-06-18"
 	}
 
-	return &Client{
+	c := &Client{
 		Host:            host,
-		SSE:             sseEnabled,
+		TransportName:   transport,
 		SID:             "",
-		CTX:             ctx,
-		Cancel:          cancel,
 		HTTPClient:      httpClient,
 		ProtocolVersion: protocolVersion,
 	}
+	if strings.HasPrefix(host, "ws://") || strings.HasPrefix(host, "wss://") {
+		transport = TransportWebSocket
+	}
+	if socket, endpoint, ok := parseUnixHost(host); ok {
+		c.Host = endpoint
+		c.UseUnixSocket(socket)
+	}
+
+	switch transport {
+	case TransportStreamableHTTP, "":
+		c.TransportName = TransportStreamableHTTP
+		c.Transport = &httpTransport{c: c}
+	case TransportSSE:
+		c.Transport = &legacySSETransport{c: c}
+	case TransportAuto:
+		c.Transport = &autoTransport{c: c}
+	case TransportWebSocket:
+		c.TransportName = TransportWebSocket
+		c.Transport = &websocketTransport{c: c}
+	default:
+		return nil, fmt.Errorf("unknown transport %q", transport)
+	}
+	return c, nil
 }
 
-func (c *Client) Call(tool, arguments string) {
-	c.sendInitializeRequest()
-	c.sendInitializedNotification()
-	c.doOperation(tool, arguments)
-}
-
-func (c *Client) ListFeature(feature, output string) {
-	c.sendInitializeRequest()
-	c.sendInitializedNotification()
-	features := c.doOperationList(feature)
-	c.display(features, output)
-}
-
-func (c *Client) Ping() {
-	c.sendInitializeRequest()
-	c.sendInitializedNotification()
-	c.ping()
-}
-
-func (c *Client) doOperation(tool, arguments string) {
-	var args interface{}
-	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
-		log.Fatal("Failed to parse arguments JSON:", err)
+// NewStdioClient starts command as a local MCP server and talks to it over
+// its stdin and stdout. What the server writes to its stderr is copied to
+// stderr, or discarded when stderr is nil. A stderr with a Flush method is
+// flushed once the server has exited.
+func NewStdioClient(command []string, stderr io.Writer, protocolVersion string) (*Client, error) {
+	if protocolVersion == "" {
+		protocolVersion = "2025-06-18"
 	}
 
-	ping := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      "123",
-		"method":  "tools/call",
-		"params": map[string]interface{}{
-			"name":      tool,
-			"arguments": args,
-		},
+	c := &Client{
+		Command:         command,
+		TransportName:   TransportStdio,
+		ProtocolVersion: protocolVersion,
 	}
-
-	pingBytes, err := json.Marshal(ping)
+	transport, err := newStdioTransport(command, stderr, c.logf)
 	if err != nil {
-		log.Fatal("Failed to marshal ping:", err)
-	}
-
-	requestMethod := "POST"
-	if c.SSE == true {
-		requestMethod = "GET"
+		return nil, err
 	}
+	c.Transport = transport
+	return c, nil
+}
 
-	pingReq, err := http.NewRequestWithContext(c.CTX, requestMethod, c.Host, bytes.NewBuffer(pingBytes))
+// Initialize performs the handshake that opens a new session. Clients
+// attached to an existing session by setting SID must not call it.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	result, err := c.sendInitializeRequest(ctx)
 	if err != nil {
-		log.Fatal("Failed to create notification request:", err)
+		return nil, err
 	}
-	pingReq.Header.Set("Content-Type", "application/json")
-	if c.SSE == true {
-		pingReq.Header.Set("Accept", "text/event-stream")
-	} else {
-		pingReq.Header.Set("Accept", "application/json")
+	c.Server = result
+	if err := c.sendInitializedNotification(ctx); err != nil {
+		return nil, err
 	}
-	pingReq.Header.Set("Mcp-Session-Id", c.SID)
+	return result, nil
+}
 
-	pingResp, err := c.HTTPClient.Do(pingReq)
-	if err != nil {
-		log.Fatal("Ping request failed:", err)
+func (c *Client) Ping(ctx context.Context) error {
+	var result map[string]interface{}
+	if err := c.request(ctx, "ping", nil, &result); err != nil {
+		return err
 	}
-	defer pingResp.Body.Close()
-
-	contentType := pingResp.Header.Get("Content-Type")
-
-	switch {
-	case contentType == "application/json":
-		var respJSON map[string]interface{}
-		if err := json.NewDecoder(pingResp.Body).Decode(&respJSON); err != nil {
-			log.Fatal("Failed to decode JSON:", err)
-		}
-		resultJSON, err := json.MarshalIndent(respJSON, "", "  ")
-		if err != nil {
-			log.Fatal("Failed to marshal feature:", err)
-		}
-
-		fmt.Println(string(resultJSON))
-
-	case contentType == "text/event-stream":
-		fmt.Println("SSE Stream detected (optional output):")
-		scanner := bufio.NewScanner(pingResp.Body)
-		go func() {
-			for scanner.Scan() {
-				line := scanner.Text()
-				fmt.Println("SSE:", line)
-			}
-			if err := scanner.Err(); err != nil {
-				fmt.Println("Error reading SSE stream:", err)
-			}
-		}()
+	if len(result) != 0 {
+		return errors.New("unexpected ping result")
+	}
+	return nil
+}
 
-	case pingResp.StatusCode == http.StatusMethodNotAllowed:
-		log.Println("Server returned 405 Method Not Allowed: SSE not offered at this endpoint")
+// ListTools returns all tools of the server, following every cursor.
+// ListToolsPages and the other *Pages methods return only some of the
+// pages, along with the cursor to continue from.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	items, _, err := c.ListToolsPages(ctx, PageOptions{})
+	return items, err
+}
 
-	default:
-		log.Printf("Unexpected response: %s, status: %d\n", contentType, pingResp.StatusCode)
-	}
+func (c *Client) ListToolsPages(ctx context.Context, opts PageOptions) ([]Tool, string, error) {
+	return listPages[Tool](ctx, c, "tools/list", "tools", opts)
 }
-func (c *Client) display(features []interface{}, output string) {
-	if output == "json" {
-		// marshal just the feature array back to JSON
-		featureJSON, err := json.MarshalIndent(features, "", "  ")
-		if err != nil {
-			log.Fatal("Failed to marshal feature:", err)
-		}
-		fmt.Println(string(featureJSON))
-	}
-	if output == "call" {
-		for _, f := range features { // features is a map, so _ = key, f = value
-			fMap, ok := f.(map[string]interface{})
-			if !ok {
-				log.Fatal("feature element is not a map")
-			}
 
-			inputSchema, ok := fMap["inputSchema"].(map[string]interface{})
-			if !ok {
-				log.Fatal("inputSchema not found")
-			}
+func (c *Client) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	items, _, err := c.ListPromptsPages(ctx, PageOptions{})
+	return items, err
+}
 
-			required, ok := inputSchema["required"].([]interface{})
-			if !ok {
-				required = []interface{}{}
-				// log.Println("required not found!")
-			}
+func (c *Client) ListPromptsPages(ctx context.Context, opts PageOptions) ([]Prompt, string, error) {
+	return listPages[Prompt](ctx, c, "prompts/list", "prompts", opts)
+}
 
-			properties, ok := inputSchema["properties"].(map[string]interface{})
-			if !ok {
-				log.Fatal("properties not found")
-			}
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	items, _, err := c.ListResourcesPages(ctx, PageOptions{})
+	return items, err
+}
 
-			toolName := strings.TrimSpace(fMap["name"].(string))
-
-			argument := ""
-			requiredSet := makeSet(required)
-			for _, key := range required {
-				keyStr := strings.TrimSpace(key.(string))
-
-				// type-assert the property to a map
-				prop, ok := properties[keyStr].(map[string]interface{})
-				if !ok {
-					log.Fatalf("property %s is not a map", keyStr)
-				}
-
-				// now access the "type" field
-				val, ok := prop["type"]
-				if !ok {
-					log.Fatalf("type not found for property %s", keyStr)
-				}
-
-				if val == "object" {
-					argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, keyStr+"Object")
-				} else if val == "enum" {
-					argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, keyStr+"Enum")
-				} else {
-					argument += fmt.Sprintf("\"%s\":\033[31m<<%s>>\033[0m,", keyStr, val)
-				}
-			}
+func (c *Client) ListResourcesPages(ctx context.Context, opts PageOptions) ([]Resource, string, error) {
+	return listPages[Resource](ctx, c, "resources/list", "resources", opts)
+}
 
-			// Only remove the last comma if argument is non-empty
-			if len(argument) > 0 {
-				argument = argument[:len(argument)-1]
-			}
-			fmt.Printf("mcpt call --host '%s' --tool '%s' --arguments '{%s}'\n", c.Host, toolName, argument)
-			traverseProperties(properties, "", requiredSet)
-			fmt.Printf("\n")
-		}
+// CallTool runs a tool. A tool that fails reports it with IsError in the
+// result rather than with an error.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*CallToolResult, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": args,
 	}
+	var result CallToolResult
+	if err := c.request(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-func makeSet(arr []interface{}) map[string]struct{} {
-	m := make(map[string]struct{}, len(arr))
-	for _, v := range arr {
-		if s, ok := v.(string); ok {
-			m[s] = struct{}{}
-		}
+func (c *Client) ReadResource(ctx context.Context, uri string) (*ReadResourceResult, error) {
+	params := map[string]interface{}{
+		"uri": uri,
+	}
+	var result ReadResourceResult
+	if err := c.request(ctx, "resources/read", params, &result); err != nil {
+		return nil, err
 	}
-	return m
+	return &result, nil
 }
 
-func traverseProperties(properties map[string]interface{}, prefix string, required map[string]struct{}) {
-	for key, val := range properties {
-		fullKey := key
-		if prefix != "" {
-			fullKey = prefix + "." + key
-		}
-
-		propMap, ok := val.(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		// print type if exists
-		if typeVal, ok := propMap["type"]; ok {
-			var t string
-			t = fmt.Sprintf("%v", typeVal) // convert interface{} → string
-			if enumVal, ok := propMap["enum"]; ok {
-				if enumArr, ok := enumVal.([]interface{}); ok {
-					t = "enum("
-					for i, e := range enumArr {
-						if i > 0 {
-							t += ","
-						}
-						t += "\"" + (e.(string)) + "\""
-					}
-					t += ")"
-				}
-			}
-			if _, exists := required[fullKey]; exists {
-				fmt.Printf("\033[31m[REQUIRED]\033[0m\033[34m %s -> type: %s\033[0m", fullKey, t)
-			} else {
-				fmt.Printf("           \033[34m%s -> type: %s\033[0m", fullKey, t)
-			}
-			if arrVal, ok := propMap["items"].(map[string]interface{}); ok {
-				for key, val := range arrVal {
-					// fmt.Printf("ArrItem %s", val)
-					if key == "type" {
-						fmt.Printf("\033[34m[ArrayItems -> type: %s]\033[0m", val)
-					}
-				}
-				_, ok := propMap["uniqueItems"]
-				if ok {
-					fmt.Printf("[UNIQUE]")
-				}
-			}
-			fmt.Printf("\n")
-
-		}
-
-		// if nested properties, recurse
-		if nested, ok := propMap["properties"]; ok {
-			if nestedProps, ok := nested.(map[string]interface{}); ok {
-				traverseProperties(nestedProps, fullKey, required)
-			}
-		}
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (*GetPromptResult, error) {
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	}
+	var result GetPromptResult
+	if err := c.request(ctx, "prompts/get", params, &result); err != nil {
+		return nil, err
 	}
+	return &result, nil
 }
 
-func (c *Client) sendInitializeRequest() {
-	reqBody := JSONRPCRequest{
-		JSONRPC: "2.0",
-		ID:      1,
-		Method:  "initialize",
-		Params: map[string]interface{}{
-			"capabilities": map[string]interface{}{
-				"textDocument": map[string]interface{}{
-					"synchronization": map[string]bool{"didSave": true},
-				},
-			},
-			"clientInfo": map[string]interface{}{
-				"name":    "go-client",
-				"version": "1.0.0",
-			},
-			"protocolVersion": c.ProtocolVersion,
+// Complete asks for completions of the argument named arg, given its
+// partial value. Arguments that are resolved already can be passed in
+// resolved; servers that implement protocol 2025-06-18 take them into
+// account.
+func (c *Client) Complete(ctx context.Context, ref CompletionReference, arg string, value string, resolved map[string]string) (*CompleteResult, error) {
+	params := map[string]interface{}{
+		"ref": ref,
+		"argument": map[string]string{
+			"name":  arg,
+			"value": value,
 		},
 	}
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		log.Fatal("Failed to marshal request:", err)
+	if len(resolved) > 0 {
+		params["context"] = map[string]interface{}{"arguments": resolved}
 	}
-
-	req, err := http.NewRequestWithContext(c.CTX, "POST", c.Host, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		log.Fatal("Failed to create request:", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		log.Fatal("Request failed:", err)
+	var result CompleteResult
+	if err := c.request(ctx, "completion/complete", params, &result); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &result, nil
+}
 
-	sessionID := resp.Header.Get("Mcp-Session-Id")
-	if sessionID == "" {
-		log.Fatal("MCP-Session-ID not found in response headers")
-	}
-	c.SID = sessionID
+// Listen passes every message the server sends on its own initiative to fn
+// until ctx is done. Requests among them are answered after fn returns.
+func (c *Client) Listen(ctx context.Context, fn func(*JSONRPCMessage)) error {
+	return c.Transport.Listen(ctx, func(msg *JSONRPCMessage) {
+		fn(msg)
+		if msg.isRequest() {
+			c.handleRequest(ctx, msg)
+		}
+	})
 }
 
-func (c *Client) sendInitializedNotification() {
-	// --- Second request: notifications/initialized ---
-	notification := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  "notifications/initialized",
-	}
+// Close releases the transport, which for stdio servers stops the subprocess
+// and for Streamable HTTP ends the session unless KeepSession is set.
+func (c *Client) Close() error {
+	return c.Transport.Close()
+}
 
-	notificationBytes, err := json.Marshal(notification)
+// request sends a JSON-RPC request and decodes the result of the response
+// into result. An error response is returned as a *JSONRPCError.
+func (c *Client) request(ctx context.Context, method string, params interface{}, result interface{}) error {
+	req, err := newMessage(c.nextID.Add(1), method, params)
 	if err != nil {
-		log.Fatal("Failed to marshal notification:", err)
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
 	}
 
-	notifReq, err := http.NewRequestWithContext(c.CTX, "POST", c.Host, bytes.NewBuffer(notificationBytes))
+	resp, err := c.Transport.RoundTrip(ctx, req, c.handler(ctx))
 	if err != nil {
-		log.Fatal("Failed to create notification request:", err)
+		if ctx.Err() != nil {
+			// the initialize request must not be cancelled
+			if method != "initialize" {
+				c.cancelRequest(ctx, req.ID)
+			}
+			err = context.Cause(ctx)
+		}
+		return fmt.Errorf("%s request failed: %w", method, err)
 	}
-	notifReq.Header.Set("Content-Type", "application/json")
-	notifReq.Header.Set("Accept", "application/json")
-	notifReq.Header.Set("Accept", "text/event-stream")
-	notifReq.Header.Set("Mcp-Session-Id", c.SID)
-
-	notifResp, err := c.HTTPClient.Do(notifReq)
-	if err != nil {
-		log.Fatal("Notification request failed:", err)
+	if resp.Error != nil {
+		return fmt.Errorf("%s: %w", method, resp.Error)
 	}
-	defer notifResp.Body.Close()
-}
-
-func (c *Client) ping() {
-	ping := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      "123",
-		"method":  "ping",
+	if result == nil {
+		return nil
 	}
-
-	pingBytes, err := json.Marshal(ping)
-	if err != nil {
-		log.Fatal("Failed to marshal ping:", err)
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
 	}
+	return nil
+}
 
-	pingReq, err := http.NewRequestWithContext(c.CTX, "POST", c.Host, bytes.NewBuffer(pingBytes))
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	msg, err := newMessage(nil, method, params)
 	if err != nil {
-		log.Fatal("Failed to create notification request:", err)
+		return fmt.Errorf("failed to marshal %s notification: %w", method, err)
 	}
-	pingReq.Header.Set("Content-Type", "application/json")
-	pingReq.Header.Set("Accept", "application/json")
-	pingReq.Header.Set("Accept", "text/event-stream")
-	pingReq.Header.Set("Mcp-Session-Id", c.SID)
-
-	pingResp, err := c.HTTPClient.Do(pingReq)
-	if err != nil {
-		log.Fatal("Ping request failed:", err)
+	if err := c.Transport.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%s notification failed: %w", method, err)
 	}
-	defer pingResp.Body.Close()
+	return nil
+}
 
-	respBody, err := io.ReadAll(pingResp.Body)
-	if err != nil {
-		log.Fatal("Failed to read response body:", err)
+// cancelRequest tells the server to stop working on the request with the
+// given id, whose ctx is done. The cause of ctx is sent as the reason.
+func (c *Client) cancelRequest(ctx context.Context, id json.RawMessage) {
+	params := map[string]interface{}{
+		"requestId": id,
+		"reason":    context.Cause(ctx).Error(),
 	}
 
-	var respJSON map[string]interface{}
-	if err := json.Unmarshal(respBody, &respJSON); err != nil {
-		log.Fatal("Failed to unmarshal response:", err)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+	if err := c.notify(ctx, "notifications/cancelled", params); err != nil {
+		c.logf("Failed to cancel request %s: %v", id, err)
 	}
-	expectedID := "123"
+}
 
-	if respJSON["jsonrpc"] != "2.0" {
-		log.Fatal("Unexpected jsonrpc value")
+// handler deals with the messages the server sends on its own initiative
+// while a request is in flight.
+func (c *Client) handler(ctx context.Context) func(*JSONRPCMessage) {
+	return func(msg *JSONRPCMessage) {
+		switch {
+		case msg.isRequest():
+			c.handleRequest(ctx, msg)
+		case msg.Method == "notifications/progress" && c.dispatchProgress(msg):
+		case msg.Method != "":
+			if c.OnNotification != nil {
+				c.OnNotification(msg)
+			}
+		default:
+			c.logf("Ignoring response to unknown request %s", msg.ID)
+		}
 	}
+}
 
-	if respJSON["id"] != expectedID {
-		log.Fatal("Unexpected id value")
+func (c *Client) handleRequest(ctx context.Context, msg *JSONRPCMessage) {
+	var result interface{}
+	var err error
+	switch msg.Method {
+	case "ping":
+		result = struct{}{}
+	case "sampling/createMessage":
+		result, err = c.handleSampling(ctx, msg.Params)
+	case "roots/list":
+		result, err = c.handleRoots()
+	case "elicitation/create":
+		result, err = c.handleElicitation(ctx, msg.Params)
+	default:
+		err = &JSONRPCError{Code: CodeMethodNotFound, Message: "Method not found: " + msg.Method}
 	}
 
-	if result, ok := respJSON["result"].(map[string]interface{}); !ok || len(result) != 0 {
-		log.Fatal("Unexpected result value")
+	resp := &JSONRPCMessage{JSONRPC: "2.0", ID: msg.ID}
+	if err == nil {
+		resp.Result, err = json.Marshal(result)
 	}
+	if err != nil {
+		resp.Error = errorResponse(err)
+	}
+	if err := c.Transport.Send(ctx, resp); err != nil {
+		c.logf("Failed to answer %s request: %v", msg.Method, err)
+	}
+}
 
-	log.Println("Ping OK ✅")
+// sessionID and setSessionID access SID for the transports, which may
+// send messages from several goroutines at once.
+func (c *Client) sessionID() string {
+	c.sidMu.Lock()
+	defer c.sidMu.Unlock()
+	return c.SID
 }
 
-func (c *Client) doOperationList(feature string) []interface{} {
-	method := feature + "/list"
-	ping := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      "123",
-		"method":  method,
-	}
+func (c *Client) setSessionID(sid string) {
+	c.sidMu.Lock()
+	defer c.sidMu.Unlock()
+	c.SID = sid
+}
 
-	pingBytes, err := json.Marshal(ping)
-	if err != nil {
-		log.Fatal("Failed to marshal ping:", err)
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, v...)
 	}
+}
 
-	pingReq, err := http.NewRequestWithContext(c.CTX, "POST", c.Host, bytes.NewBuffer(pingBytes))
-	if err != nil {
-		log.Fatal("Failed to create notification request:", err)
+func (c *Client) sendInitializeRequest(ctx context.Context) (*InitializeResult, error) {
+	capabilities := map[string]interface{}{}
+	if c.Roots != nil {
+		capabilities["roots"] = map[string]bool{"listChanged": true}
 	}
-	pingReq.Header.Set("Content-Type", "application/json")
-	pingReq.Header.Set("Accept", "application/json")
-	pingReq.Header.Set("Accept", "text/event-stream")
-	pingReq.Header.Set("Mcp-Session-Id", c.SID)
-
-	pingResp, err := c.HTTPClient.Do(pingReq)
-	if err != nil {
-		log.Fatal("Ping request failed:", err)
+	if c.Sampling != nil {
+		capabilities["sampling"] = struct{}{}
 	}
-	defer pingResp.Body.Close()
-
-	var respJSON map[string]interface{}
-	if err := json.NewDecoder(pingResp.Body).Decode(&respJSON); err != nil {
-		log.Fatal("Failed to decode JSON:", err)
+	if c.Elicitation != nil {
+		capabilities["elicitation"] = struct{}{}
 	}
 
-	// drill into result
-	result, ok := respJSON["result"].(map[string]interface{})
-	if !ok {
-		log.Fatal("result not found or wrong type")
+	params := map[string]interface{}{
+		"capabilities": capabilities,
+		"clientInfo": map[string]interface{}{
+			"name":    "go-client",
+			"version": "1.0.0",
+		},
+		"protocolVersion": c.ProtocolVersion,
 	}
-
-	// drill into feature
-	features, ok := result[feature].([]interface{})
-	if !ok {
-		log.Fatal("feature not found or wrong type")
+	var raw json.RawMessage
+	if err := c.request(ctx, "initialize", params, &raw); err != nil {
+		return nil, err
 	}
+	var result InitializeResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid initialize result: %w", err)
+	}
+	result.Raw = raw
+	return &result, nil
+}
 
-	return features
-
+func (c *Client) sendInitializedNotification(ctx context.Context) error {
+	return c.notify(ctx, "notifications/initialized", nil)
 }
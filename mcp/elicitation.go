@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Actions a user can take on an elicitation request.
+const (
+	ElicitAccept  = "accept"
+	ElicitDecline = "decline"
+	ElicitCancel  = "cancel"
+)
+
+// ElicitRequest holds the params of an elicitation/create request: a
+// message for the user and the flat form they are asked to fill in.
+type ElicitRequest struct {
+	Message         string       `json:"message"`
+	RequestedSchema ElicitSchema `json:"requestedSchema"`
+}
+
+// ElicitSchema is the restricted JSON schema of an elicitation: an object
+// whose properties all have a primitive type.
+type ElicitSchema struct {
+	Type       string                      `json:"type"`
+	Properties map[string]*PrimitiveSchema `json:"properties"`
+	Required   []string                    `json:"required,omitempty"`
+	// Order lists the names of Properties in the order the server sent
+	// them, which is the order to ask for them in.
+	Order []string `json:"-"`
+}
+
+// PrimitiveSchema describes one field of an elicitation form: a string,
+// number, integer or boolean, or a string with Enum values.
+type PrimitiveSchema struct {
+	Type        string      `json:"type"`
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+	EnumNames   []string    `json:"enumNames,omitempty"`
+	Format      string      `json:"format,omitempty"`
+	MinLength   *int        `json:"minLength,omitempty"`
+	MaxLength   *int        `json:"maxLength,omitempty"`
+	Minimum     *float64    `json:"minimum,omitempty"`
+	Maximum     *float64    `json:"maximum,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+func (s *ElicitSchema) UnmarshalJSON(data []byte) error {
+	type plain ElicitSchema
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw.Properties) == 0 {
+		return err
+	}
+	s.Order = nil
+	dec := json.NewDecoder(bytes.NewReader(raw.Properties))
+	// skip the opening brace, then read the keys one value after the other
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		s.Order = append(s.Order, fmt.Sprint(key))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRequired reports whether the form cannot be accepted without name.
+func (s *ElicitSchema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// ElicitationHandler answers the elicitation/create requests of a server.
+type ElicitationHandler func(ctx context.Context, req *ElicitRequest) (*ElicitResult, error)
+
+func (c *Client) handleElicitation(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	if c.Elicitation == nil {
+		return nil, &JSONRPCError{Code: CodeMethodNotFound, Message: "Elicitation is not supported"}
+	}
+	var req ElicitRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &JSONRPCError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	result, err := c.Elicitation(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if result.Action != ElicitAccept {
+		result.Content = nil
+	}
+	return result, nil
+}
@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// httpTransport posts every message to Client.Host and keeps track of the
+// session id the server hands out.
+type httpTransport struct {
+	c *Client
+}
+
+func (t *httpTransport) post(ctx context.Context, msg *JSONRPCMessage) (*http.Response, error) {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", t.c.Host, bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setSessionHeaders(req)
+
+	resp, err := t.c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		t.c.setSessionID(sessionID)
+	}
+	return resp, nil
+}
+
+// setSessionHeaders adds the session id and, once the session has been
+// initialized, the negotiated protocol version, which protocol 2025-06-18
+// requires on every request after initialize.
+func (t *httpTransport) setSessionHeaders(req *http.Request) {
+	sid := t.c.sessionID()
+	if sid != "" {
+		req.Header.Set("Mcp-Session-Id", sid)
+	}
+	if t.c.Server != nil {
+		req.Header.Set("MCP-Protocol-Version", t.c.Server.ProtocolVersion)
+	} else if sid != "" {
+		// attached to a session we did not initialize: assume it speaks
+		// the version we would have asked for
+		req.Header.Set("MCP-Protocol-Version", t.c.ProtocolVersion)
+	}
+}
+
+// HTTPError reports a request the server turned down with an HTTP error status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return "server returned " + e.Status
+	}
+	return fmt.Sprintf("server returned %s: %s", e.Status, e.Body)
+}
+
+func newHTTPError(resp *http.Response) *HTTPError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+func (t *httpTransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return t.readResponse(ctx, resp, idKey(req.ID), handle)
+}
+
+// readResponse reads the answer to a POSTed request, which the server may
+// send either as a single JSON body or as an SSE stream that carries other
+// messages before the response.
+func (t *httpTransport) readResponse(ctx context.Context, resp *http.Response, id string, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	if sid := t.c.sessionID(); resp.StatusCode == http.StatusNotFound && sid != "" {
+		return nil, fmt.Errorf("session %s is no longer known to the server: %w", sid, newHTTPError(resp))
+	}
+	if resp.StatusCode >= 400 {
+		return nil, newHTTPError(resp)
+	}
+	contentType := resp.Header.Get("Content-Type")
+
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		var msg JSONRPCMessage
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+		return &msg, nil
+
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		msg, err := t.followStream(ctx, resp.Body, id, handle, false)
+		if err != nil {
+			return nil, fmt.Errorf("no response to request %s: %w", id, err)
+		}
+		return msg, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected response: %s, status: %d", contentType, resp.StatusCode)
+	}
+}
+
+func (t *httpTransport) Send(ctx context.Context, msg *JSONRPCMessage) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return newHTTPError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Listen opens the optional GET stream the server uses to send messages
+// that are not tied to any request of ours.
+func (t *httpTransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	body, err := t.openStream(ctx, "")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	_, err = t.followStream(ctx, body, "", handle, true)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// openStream issues the GET that opens an SSE stream on the session, or
+// resumes one after the event lastEventID.
+func (t *httpTransport) openStream(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", t.c.Host, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	t.setSessionHeaders(req)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := t.c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("server does not offer an SSE stream at this endpoint: %w", newHTTPError(resp))
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, newHTTPError(resp)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		resp.Body.Close()
+		return nil, fmt.Errorf("server did not open an SSE stream: %s", contentType)
+	}
+	return resp.Body, nil
+}
+
+// maxReconnects bounds how many times in a row a dropped stream is resumed
+// without receiving a single event in between.
+const maxReconnects = 5
+
+// defaultRetry is the reconnection delay used until the server sets one
+// with a retry field.
+const defaultRetry = time.Second
+
+// followStream reads messages from an SSE body until the response with id
+// arrives. When the stream drops, it waits for the server's retry delay
+// and resumes it with a GET carrying the last event id it saw. A stream
+// without event ids cannot be resumed, unless always is set, in which case
+// a fresh stream is opened instead. The body is closed on return.
+func (t *httpTransport) followStream(ctx context.Context, body io.ReadCloser, id string, handle func(*JSONRPCMessage), always bool) (*JSONRPCMessage, error) {
+	lastEventID := ""
+	retry := defaultRetry
+	reconnects := 0
+
+	for {
+		events := newSSEReader(body)
+		var err error
+		for {
+			var ev *sseEvent
+			ev, err = events.next()
+			if err != nil {
+				break
+			}
+			reconnects = 0
+			if ev.ID != "" {
+				lastEventID = ev.ID
+			}
+			if ev.Retry > 0 {
+				retry = ev.Retry
+			}
+			if !ev.isMessage() {
+				continue
+			}
+
+			msg, perr := parseMessage([]byte(ev.Data))
+			if perr != nil {
+				t.c.logf("Ignoring malformed SSE event: %v", perr)
+				continue
+			}
+			if id != "" && msg.isResponse() && idKey(msg.ID) == id {
+				body.Close()
+				return msg, nil
+			}
+			handle(msg)
+		}
+		body.Close()
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == io.EOF {
+			err = errors.New("server closed the SSE stream")
+		}
+		if lastEventID == "" && !always {
+			return nil, err
+		}
+		if reconnects++; reconnects > maxReconnects {
+			return nil, fmt.Errorf("giving up after %d reconnections: %w", maxReconnects, err)
+		}
+
+		if lastEventID != "" {
+			t.c.logf("SSE stream dropped (%v), resuming after event %s in %s", err, lastEventID, retry)
+		} else {
+			t.c.logf("SSE stream dropped (%v), reconnecting in %s", err, retry)
+		}
+		select {
+		case <-time.After(retry):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		body, err = t.openStream(ctx, lastEventID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sessionCloseTimeout bounds the DELETE that ends a session, which is sent
+// when the request context may already be done.
+const sessionCloseTimeout = 5 * time.Second
+
+// Close terminates the session with a DELETE, as the server cannot tell
+// otherwise that we are gone.
+func (t *httpTransport) Close() error {
+	if t.c.sessionID() == "" || t.c.KeepSession {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCloseTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", t.c.Host, nil)
+	if err != nil {
+		return err
+	}
+	t.setSessionHeaders(req)
+
+	resp, err := t.c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to terminate session: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed:
+		// the server does not let clients terminate sessions
+	case resp.StatusCode/100 != 2:
+		return fmt.Errorf("failed to terminate session: %w", newHTTPError(resp))
+	}
+	t.c.setSessionID("")
+	return nil
+}
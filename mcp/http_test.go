@@ -0,0 +1,404 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamResume(t *testing.T) {
+	tests := []struct {
+		name string
+		// post is the SSE body answering the POST; %s is the request id
+		post string
+		// get is the SSE body answering the resuming GET
+		get         string
+		wantErr     string
+		wantResumes []string
+	}{
+		{
+			name:        "resumes after the last event id",
+			post:        "retry: 10\nid: 1\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n",
+			get:         "id: 2\ndata: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{}}\n\n",
+			wantResumes: []string{"1"},
+		},
+		{
+			name:        "keeps the last id across resumes",
+			post:        "retry: 10\nid: 7\n\n",
+			get:         ": nothing yet\n\n",
+			wantErr:     "giving up after 5 reconnections",
+			wantResumes: []string{"7", "7", "7", "7", "7"},
+		},
+		{
+			name:    "stream without ids is not resumed",
+			post:    "retry: 10\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n",
+			wantErr: "server closed the SSE stream",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var id string
+			var resumes []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				w.Header().Set("Content-Type", "text/event-stream")
+				switch r.Method {
+				case "POST":
+					var msg JSONRPCMessage
+					if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+						t.Errorf("bad request body: %v", err)
+					}
+					id = string(msg.ID)
+					fmt.Fprint(w, strings.ReplaceAll(tt.post, "%s", id))
+				case "GET":
+					if got := r.Header.Get("Mcp-Session-Id"); got != "s1" {
+						t.Errorf("GET Mcp-Session-Id = %q, want s1", got)
+					}
+					if got := r.Header.Get("MCP-Protocol-Version"); got != "2025-06-18" {
+						t.Errorf("GET MCP-Protocol-Version = %q, want 2025-06-18", got)
+					}
+					resumes = append(resumes, r.Header.Get("Last-Event-ID"))
+					fmt.Fprint(w, strings.ReplaceAll(tt.get, "%s", id))
+				default:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, TransportStreamableHTTP, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.SID = "s1"
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = c.Ping(ctx)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Ping failed: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Ping error = %v, want %q", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(resumes, ",") != strings.Join(tt.wantResumes, ",") {
+				t.Errorf("Last-Event-ID of the GETs = %q, want %q", resumes, tt.wantResumes)
+			}
+		})
+	}
+}
+
+// legacyServer is a 2024-11-05 HTTP+SSE server: GET opens the event stream,
+// which starts with preamble, and every request POSTed to the endpoint is
+// answered on the stream after the events of before.
+type legacyServer struct {
+	preamble string
+	before   []string
+	events   chan string
+	mu       sync.Mutex
+	posts    []string
+}
+
+func newLegacyServer(preamble string, before ...string) *legacyServer {
+	return &legacyServer{preamble: preamble, before: before, events: make(chan string, 16)}
+}
+
+func (s *legacyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, s.preamble)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case ev := <-s.events:
+				fmt.Fprint(w, ev)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	case "POST":
+		var msg JSONRPCMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.posts = append(s.posts, r.URL.RequestURI())
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+		if msg.isRequest() {
+			for _, ev := range s.before {
+				s.events <- ev
+			}
+			s.events <- fmt.Sprintf("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{}}\n\n", msg.ID)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestLegacySSE(t *testing.T) {
+	const notice = "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\",\"params\":{}}\n\n"
+
+	tests := []struct {
+		name     string
+		preamble string
+		before   []string
+		// wantPost is where the request was POSTed to
+		wantPost          string
+		wantNotifications int
+		wantErr           string
+	}{
+		{
+			name:     "relative endpoint",
+			preamble: "event: endpoint\ndata: /messages?session=1\n\n",
+			wantPost: "/messages?session=1",
+		},
+		{
+			name:     "events before the endpoint are skipped",
+			preamble: ": hello\n\nevent: ping\ndata: x\n\nevent: endpoint\ndata: messages?session=2\n\n",
+			wantPost: "/messages?session=2",
+		},
+		{
+			name:              "notifications before the response",
+			preamble:          "event: endpoint\ndata: /messages\n\n",
+			before:            []string{notice, ": keep-alive\n\n", notice},
+			wantPost:          "/messages",
+			wantNotifications: 2,
+		},
+		{
+			name:     "no endpoint event",
+			preamble: "event: message\ndata: {}\n\n",
+			wantErr:  "SSE stream ended before the endpoint event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacy := newLegacyServer(tt.preamble, tt.before...)
+			handler := http.Handler(legacy)
+			if tt.wantErr != "" {
+				// end the stream right after the preamble
+				handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, tt.preamble)
+				})
+			}
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL+"/sse", TransportSSE, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			notifications := 0
+			c.OnNotification = func(*JSONRPCMessage) { notifications++ }
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = c.Ping(ctx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Ping error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Ping failed: %v", err)
+			}
+
+			legacy.mu.Lock()
+			defer legacy.mu.Unlock()
+			if len(legacy.posts) != 1 || legacy.posts[0] != tt.wantPost {
+				t.Errorf("POSTed to %q, want %q", legacy.posts, tt.wantPost)
+			}
+			if notifications != tt.wantNotifications {
+				t.Errorf("got %d notifications, want %d", notifications, tt.wantNotifications)
+			}
+		})
+	}
+}
+
+func TestAutoTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		// status answers the Streamable HTTP POST, 200 being a JSON response
+		status        int
+		wantTransport string
+		wantErr       string
+	}{
+		{name: "streamable HTTP", status: http.StatusOK, wantTransport: TransportStreamableHTTP},
+		{name: "405 falls back to legacy SSE", status: http.StatusMethodNotAllowed, wantTransport: TransportSSE},
+		{name: "404 falls back to legacy SSE", status: http.StatusNotFound, wantTransport: TransportSSE},
+		{name: "400 falls back to legacy SSE", status: http.StatusBadRequest, wantTransport: TransportSSE},
+		{name: "5xx does not fall back", status: http.StatusInternalServerError, wantTransport: TransportAuto, wantErr: "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacy := newLegacyServer("event: endpoint\ndata: /messages\n\n")
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/mcp" || r.Method != "POST" {
+					legacy.ServeHTTP(w, r)
+					return
+				}
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+				var msg JSONRPCMessage
+				json.NewDecoder(r.Body).Decode(&msg)
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, msg.ID)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL+"/mcp", TransportAuto, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = c.Ping(ctx)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Ping failed: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Ping error = %v, want %q", err, tt.wantErr)
+			}
+			if c.TransportName != tt.wantTransport {
+				t.Errorf("TransportName = %q, want %q", c.TransportName, tt.wantTransport)
+			}
+			if tt.wantTransport == TransportSSE {
+				// the transport picked is kept for the rest of the session
+				if err := c.Ping(ctx); err != nil {
+					t.Fatalf("second Ping failed: %v", err)
+				}
+				legacy.mu.Lock()
+				defer legacy.mu.Unlock()
+				if len(legacy.posts) != 2 {
+					t.Errorf("legacy endpoint got %d POSTs, want 2", len(legacy.posts))
+				}
+			}
+		})
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		// newContext ends shortly after the server got the request
+		newContext func() (context.Context, context.CancelFunc)
+		initialize bool
+		wantReason string
+	}{
+		{
+			name: "timeout",
+			newContext: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeoutCause(context.Background(), 50*time.Millisecond, errors.New("timed out after 50ms"))
+			},
+			wantReason: "timed out after 50ms",
+		},
+		{
+			name: "interrupt",
+			newContext: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(50*time.Millisecond, func() { cancel(errors.New("interrupted by user")) })
+				return ctx, func() { cancel(context.Canceled) }
+			},
+			wantReason: "interrupted by user",
+		},
+		{
+			name: "initialize is not cancelled",
+			newContext: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeoutCause(context.Background(), 50*time.Millisecond, errors.New("timed out after 50ms"))
+			},
+			initialize: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requestID string
+			var cancelled []map[string]interface{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var msg JSONRPCMessage
+				if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+					t.Errorf("bad request body: %v", err)
+				}
+				if msg.isRequest() {
+					mu.Lock()
+					requestID = string(msg.ID)
+					mu.Unlock()
+					// never answer, until the client gives up
+					<-r.Context().Done()
+					return
+				}
+				if msg.Method == "notifications/cancelled" {
+					var params map[string]interface{}
+					json.Unmarshal(msg.Params, &params)
+					mu.Lock()
+					cancelled = append(cancelled, params)
+					mu.Unlock()
+				}
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, TransportStreamableHTTP, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := tt.newContext()
+			defer cancel()
+
+			if tt.initialize {
+				_, err = c.Initialize(ctx)
+			} else {
+				err = c.Ping(ctx)
+			}
+			if err == nil {
+				t.Fatal("request succeeded, want it cancelled")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if tt.wantReason == "" {
+				if len(cancelled) != 0 {
+					t.Fatalf("server got notifications/cancelled %v, want none", cancelled)
+				}
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("error = %v, want the cause %q", err, tt.wantReason)
+			}
+			if len(cancelled) != 1 {
+				t.Fatalf("server got %d notifications/cancelled, want 1", len(cancelled))
+			}
+			if id := fmt.Sprint(cancelled[0]["requestId"]); id != requestID {
+				t.Errorf("requestId = %s, want %s", id, requestID)
+			}
+			if reason := cancelled[0]["reason"]; reason != tt.wantReason {
+				t.Errorf("reason = %v, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONRPCMessage is any JSON-RPC 2.0 message: a request, a notification or a response.
+type JSONRPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+type JSONRPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC error %d: %s", e.Code, e.Message)
+}
+
+// JSON-RPC error codes used when answering server requests.
+const (
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeUserRejected is what clients answer a request the user declined with.
+	CodeUserRejected = -1
+)
+
+func newMessage(id interface{}, method string, params interface{}) (*JSONRPCMessage, error) {
+	msg := &JSONRPCMessage{JSONRPC: "2.0", Method: method}
+	if id != nil {
+		idBytes, err := json.Marshal(id)
+		if err != nil {
+			return nil, err
+		}
+		msg.ID = idBytes
+	}
+	if params != nil {
+		paramsBytes, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = paramsBytes
+	}
+	return msg, nil
+}
+
+func (m *JSONRPCMessage) isResponse() bool {
+	return m.Method == "" && len(m.ID) > 0
+}
+
+func (m *JSONRPCMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+// idKey normalises a raw id so the same id compares equal however it was spaced.
+func idKey(id json.RawMessage) string {
+	return string(bytes.TrimSpace(id))
+}
+
+func parseMessage(data []byte) (*JSONRPCMessage, error) {
+	var msg JSONRPCMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	if msg.JSONRPC != "2.0" {
+		return nil, fmt.Errorf("unexpected jsonrpc value %q", msg.JSONRPC)
+	}
+	return &msg, nil
+}
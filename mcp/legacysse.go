@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// legacySSETransport speaks the HTTP+SSE transport of protocol 2024-11-05:
+// the server streams every message over one GET request and names, in its
+// first "endpoint" event, the URL the client has to POST its messages to.
+type legacySSETransport struct {
+	c        *Client
+	mu       sync.Mutex
+	endpoint string
+	cancel   context.CancelFunc
+	stream   *messageStream
+}
+
+// connect opens the event stream the first time the transport is used.
+func (t *legacySSETransport) connect(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stream != nil {
+		return nil
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	// only the wait for the endpoint is bound to ctx, not the stream itself
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	req, err := http.NewRequestWithContext(streamCtx, "GET", t.c.Host, nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := t.c.HTTPClient.Do(req)
+	if err != nil {
+		cancel()
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer cancel()
+		defer resp.Body.Close()
+		return newHTTPError(resp)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body.Close()
+		cancel()
+		return fmt.Errorf("server did not open an SSE stream: %s", resp.Header.Get("Content-Type"))
+	}
+
+	events := newSSEReader(resp.Body)
+	endpoint, err := t.readEndpoint(events)
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	t.endpoint = endpoint
+	t.cancel = cancel
+	t.stream = newMessageStream()
+	go t.readMessages(events, resp.Body)
+	return nil
+}
+
+func (t *legacySSETransport) readEndpoint(events *sseReader) (string, error) {
+	for {
+		ev, err := events.next()
+		if err == io.EOF {
+			return "", errors.New("SSE stream ended before the endpoint event")
+		}
+		if err != nil {
+			return "", err
+		}
+		if ev.Event != "endpoint" {
+			continue
+		}
+
+		base, err := url.Parse(t.c.Host)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(strings.TrimSpace(ev.Data))
+		if err != nil {
+			return "", fmt.Errorf("invalid endpoint %q: %w", ev.Data, err)
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+}
+
+func (t *legacySSETransport) readMessages(events *sseReader, body io.ReadCloser) {
+	defer body.Close()
+	for {
+		ev, err := events.next()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("server closed the SSE stream")
+			}
+			t.stream.finish(err)
+			return
+		}
+		if !ev.isMessage() {
+			continue
+		}
+
+		msg, err := parseMessage([]byte(ev.Data))
+		if err != nil {
+			t.c.logf("Ignoring malformed SSE event: %v", err)
+			continue
+		}
+		if !t.stream.deliver(msg) {
+			return
+		}
+	}
+}
+
+func (t *legacySSETransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	if err := t.Send(ctx, req); err != nil {
+		return nil, err
+	}
+	return t.stream.await(ctx, idKey(req.ID), handle)
+}
+
+func (t *legacySSETransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+	return t.stream.listen(ctx, handle)
+}
+
+func (t *legacySSETransport) Send(ctx context.Context, msg *JSONRPCMessage) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return newHTTPError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (t *legacySSETransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stream != nil {
+		t.stream.close()
+		t.cancel()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// LogLevels are the syslog severities accepted by SetLogLevel, from the
+// most verbose to the least.
+var LogLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// LogMessage is the payload of a notifications/message notification.
+type LogMessage struct {
+	Level  string          `json:"level"`
+	Logger string          `json:"logger,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// SetLogLevel asks the server to send log messages of level and above.
+// Only servers that advertise the logging capability support it.
+func (c *Client) SetLogLevel(ctx context.Context, level string) error {
+	return c.request(ctx, "logging/setLevel", map[string]interface{}{"level": level}, nil)
+}
+
+// ParseLogMessage returns the log message carried by a notifications/message
+// notification, or false for any other message.
+func ParseLogMessage(msg *JSONRPCMessage) (*LogMessage, bool) {
+	if msg.Method != "notifications/message" {
+		return nil, false
+	}
+	var logMsg LogMessage
+	if err := json.Unmarshal(msg.Params, &logMsg); err != nil {
+		return nil, false
+	}
+	return &logMsg, true
+}
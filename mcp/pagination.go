@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// PageOptions controls how a list request follows the nextCursor of the
+// server.
+type PageOptions struct {
+	// Cursor is where to start, or "" for the first page.
+	Cursor string
+	// MaxPages stops the listing after this many pages; 0 follows every
+	// cursor to the end.
+	MaxPages int
+	// MaxItems stops the listing at the end of the page on which this many
+	// items have been collected; 0 means no limit. MCP has no way to ask for
+	// smaller pages, so the last page is kept whole and may take the listing
+	// past MaxItems.
+	MaxItems int
+}
+
+// listPages sends method once per page and collects the items found under
+// key in every result. It returns the cursor of the next page when it
+// stopped because of opts.MaxPages or opts.MaxItems, and fails on a cursor that was seen
+// before, which would otherwise loop forever.
+func listPages[T any](ctx context.Context, c *Client, method string, key string, opts PageOptions) ([]T, string, error) {
+	items := []T{}
+	cursor := opts.Cursor
+	seen := map[string]bool{}
+	for page := 1; ; page++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+
+		var result map[string]json.RawMessage
+		if err := c.request(ctx, method, params, &result); err != nil {
+			return nil, "", err
+		}
+		var pageItems []T
+		if raw, ok := result[key]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, "", fmt.Errorf("invalid %s result: %w", method, err)
+			}
+		}
+		items = append(items, pageItems...)
+
+		var next string
+		if raw, ok := result["nextCursor"]; ok && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &next); err != nil {
+				return nil, "", fmt.Errorf("invalid nextCursor in %s result: %w", method, err)
+			}
+		}
+		if next == "" {
+			return items, "", nil
+		}
+		seen[cursor] = true
+		if seen[next] {
+			return nil, "", fmt.Errorf("%s: server returned cursor %q again after %d pages", method, next, page)
+		}
+		cursor = next
+
+		if opts.MaxPages > 0 && page >= opts.MaxPages || opts.MaxItems > 0 && len(items) >= opts.MaxItems {
+			return items, cursor, nil
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pageTransport answers tools/list with one page per cursor, each page
+// holding a single tool named after the cursor.
+type pageTransport struct {
+	// next maps the cursor of a page to the nextCursor it returns
+	next map[string]string
+}
+
+func (p *pageTransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	var params struct {
+		Cursor string `json:"cursor"`
+	}
+	if req.Params != nil {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	result := map[string]interface{}{
+		"tools": []map[string]string{{"name": "page" + params.Cursor}},
+	}
+	if next := p.next[params.Cursor]; next != "" {
+		result["nextCursor"] = next
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONRPCMessage{JSONRPC: "2.0", ID: req.ID, Result: data}, nil
+}
+
+func (p *pageTransport) Send(ctx context.Context, msg *JSONRPCMessage) error { return nil }
+
+func (p *pageTransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	return nil
+}
+
+func (p *pageTransport) Close() error { return nil }
+
+func TestListPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		next      map[string]string
+		opts      PageOptions
+		wantTools []string
+		wantNext  string
+		wantErr   string
+	}{
+		{
+			name:      "single page",
+			wantTools: []string{"page"},
+		},
+		{
+			name:      "follows every cursor",
+			next:      map[string]string{"": "a", "a": "b"},
+			wantTools: []string{"page", "pagea", "pageb"},
+		},
+		{
+			name:      "starts at the given cursor",
+			next:      map[string]string{"": "a", "a": "b"},
+			opts:      PageOptions{Cursor: "a"},
+			wantTools: []string{"pagea", "pageb"},
+		},
+		{
+			name:      "stops after max pages",
+			next:      map[string]string{"": "a", "a": "b"},
+			opts:      PageOptions{MaxPages: 2},
+			wantTools: []string{"page", "pagea"},
+			wantNext:  "b",
+		},
+		{
+			name:      "stops at the page that reaches max items",
+			next:      map[string]string{"": "a", "a": "b", "b": "c"},
+			opts:      PageOptions{MaxItems: 2},
+			wantTools: []string{"page", "pagea"},
+			wantNext:  "b",
+		},
+		{
+			name:      "max items beyond the end",
+			next:      map[string]string{"": "a"},
+			opts:      PageOptions{MaxItems: 5},
+			wantTools: []string{"page", "pagea"},
+		},
+		{
+			name:    "cursor pointing to itself",
+			next:    map[string]string{"": "a", "a": "a"},
+			wantErr: `server returned cursor "a" again after 2 pages`,
+		},
+		{
+			name:    "cursor loop over several pages",
+			next:    map[string]string{"": "a", "a": "b", "b": "c", "c": "a"},
+			wantErr: `server returned cursor "a" again after 4 pages`,
+		},
+		{
+			name:    "cursor back to the start",
+			next:    map[string]string{"a": "b", "b": "a"},
+			opts:    PageOptions{Cursor: "a"},
+			wantErr: `server returned cursor "a" again after 2 pages`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{Transport: &pageTransport{next: tt.next}}
+			tools, next, err := c.ListToolsPages(context.Background(), tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListToolsPages failed: %v", err)
+			}
+
+			var names []string
+			for _, tool := range tools {
+				names = append(names, tool.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantTools) {
+				t.Errorf("tools = %v, want %v", names, tt.wantTools)
+			}
+			if next != tt.wantNext {
+				t.Errorf("next cursor = %q, want %q", next, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Progress is the payload of a notifications/progress notification. Total
+// is 0 when the server does not know how much work there is.
+type Progress struct {
+	ProgressToken json.RawMessage `json:"progressToken"`
+	Progress      float64         `json:"progress"`
+	Total         float64         `json:"total,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
+
+// CallToolWithProgress is CallTool with a progress token attached to the
+// request, so that the server can report its progress to onProgress while
+// the tool runs.
+func (c *Client) CallToolWithProgress(ctx context.Context, name string, args map[string]interface{}, onProgress func(*Progress)) (*CallToolResult, error) {
+	token := strconv.FormatInt(c.nextID.Add(1), 10)
+	c.progress.Store(token, onProgress)
+	defer c.progress.Delete(token)
+
+	params := map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+		"_meta":     map[string]interface{}{"progressToken": token},
+	}
+	var result CallToolResult
+	if err := c.request(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// dispatchProgress passes a progress notification to the callback waiting
+// for its token and reports whether there was one.
+func (c *Client) dispatchProgress(msg *JSONRPCMessage) bool {
+	var progress Progress
+	if err := json.Unmarshal(msg.Params, &progress); err != nil {
+		return false
+	}
+	var token string
+	if err := json.Unmarshal(progress.ProgressToken, &token); err != nil {
+		return false
+	}
+	fn, ok := c.progress.Load(token)
+	if !ok {
+		return false
+	}
+	fn.(func(*Progress))(&progress)
+	return true
+}
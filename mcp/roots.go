@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+)
+
+// Root is a directory or file the server may work in.
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// SetRoots replaces the roots offered to the server and tells it that they
+// changed, so that it asks for them again.
+func (c *Client) SetRoots(ctx context.Context, roots []Root) error {
+	c.rootsMu.Lock()
+	c.Roots = roots
+	c.rootsMu.Unlock()
+	return c.notify(ctx, "notifications/roots/list_changed", nil)
+}
+
+func (c *Client) handleRoots() (interface{}, error) {
+	c.rootsMu.Lock()
+	defer c.rootsMu.Unlock()
+	if c.Roots == nil {
+		return nil, &JSONRPCError{Code: CodeMethodNotFound, Message: "Roots are not supported"}
+	}
+	return map[string]interface{}{"roots": c.Roots}, nil
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// SamplingMessage is one message of the conversation a server asks the
+// client to continue.
+type SamplingMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+// CreateMessageRequest holds the params of a sampling/createMessage request.
+type CreateMessageRequest struct {
+	Messages         []SamplingMessage      `json:"messages"`
+	ModelPreferences map[string]interface{} `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"`
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	Meta             map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type CreateMessageResult struct {
+	Role       string  `json:"role"`
+	Content    Content `json:"content"`
+	Model      string  `json:"model"`
+	StopReason string  `json:"stopReason,omitempty"`
+}
+
+// SamplingHandler answers the sampling/createMessage requests of a server.
+// Returning a *JSONRPCError sends that error to the server, for instance
+// one with CodeUserRejected.
+type SamplingHandler func(ctx context.Context, req *CreateMessageRequest) (*CreateMessageResult, error)
+
+func (c *Client) handleSampling(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	if c.Sampling == nil {
+		return nil, &JSONRPCError{Code: CodeMethodNotFound, Message: "Sampling is not supported"}
+	}
+	var req CreateMessageRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &JSONRPCError{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	result, err := c.Sampling(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if result.Role == "" {
+		result.Role = "assistant"
+	}
+	return result, nil
+}
+
+// errorResponse turns an error of a request handler into the error sent
+// back to the server.
+func errorResponse(err error) *JSONRPCError {
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return &JSONRPCError{Code: CodeInternalError, Message: err.Error()}
+}
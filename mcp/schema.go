@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// ValidateStructuredContent checks the structuredContent of a tool result
+// against the outputSchema the tool declares. Results of tools without an
+// outputSchema always pass.
+func ValidateStructuredContent(tool *Tool, result *CallToolResult) error {
+	if tool.OutputSchema == nil {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return errors.New("the tool declares an outputSchema but returned no structuredContent")
+	}
+
+	data, err := json.Marshal(tool.OutputSchema)
+	if err != nil {
+		return err
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("invalid outputSchema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("invalid outputSchema: %w", err)
+	}
+	return resolved.Validate(result.StructuredContent)
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sseEvent is one event read from a text/event-stream body.
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// sseReader parses a text/event-stream body as laid out in the HTML
+// server-sent events specification.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next event. Events that only carry an id or a retry
+// delay are returned as well, with empty Data, so callers can track them.
+func (s *sseReader) next() (*sseEvent, error) {
+	var ev sseEvent
+	var data []string
+	seen := false
+
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if line == "" {
+			if seen {
+				ev.Data = strings.Join(data, "\n")
+				return &ev, nil
+			}
+			if err == io.EOF {
+				return nil, err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			// comment, used by servers as keep-alive
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data = append(data, value)
+			seen = true
+		case "event":
+			ev.Event = value
+			seen = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				ev.ID = value
+				seen = true
+			}
+		case "retry":
+			if ms, perr := strconv.Atoi(value); perr == nil && ms >= 0 {
+				ev.Retry = time.Duration(ms) * time.Millisecond
+				seen = true
+			}
+		}
+
+		if err == io.EOF {
+			// an event that is not terminated by a blank line is discarded
+			return nil, err
+		}
+	}
+}
+
+// isMessage reports whether ev carries a JSON-RPC message.
+func (ev *sseEvent) isMessage() bool {
+	return ev.Data != "" && (ev.Event == "" || ev.Event == "message")
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []sseEvent
+	}{
+		{
+			name:  "single data line",
+			input: "data: {\"a\":1}\n\n",
+			want:  []sseEvent{{Data: `{"a":1}`}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: one\ndata: two\ndata:three\n\n",
+			want:  []sseEvent{{Data: "one\ntwo\nthree"}},
+		},
+		{
+			name:  "id and event",
+			input: "event: message\nid: 42\ndata: x\n\n",
+			want:  []sseEvent{{ID: "42", Event: "message", Data: "x"}},
+		},
+		{
+			name:  "retry alone is an event",
+			input: "retry: 1500\n\ndata: x\n\n",
+			want:  []sseEvent{{Retry: 1500 * time.Millisecond}, {Data: "x"}},
+		},
+		{
+			name:  "invalid retry is ignored",
+			input: "retry: soon\ndata: x\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "id with NUL is ignored",
+			input: "id: a\x00b\ndata: x\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "comments",
+			input: ": keep-alive\n\n:ping\ndata: x\n: between\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "id: 1\r\ndata: x\r\n\r\n",
+			want:  []sseEvent{{ID: "1", Data: "x"}},
+		},
+		{
+			name:  "unknown fields",
+			input: "foo: bar\ndata: x\n\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "unterminated event at EOF is discarded",
+			input: "data: x\n\ndata: y\n",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "unterminated line at EOF is discarded",
+			input: "data: x\n\ndata: y",
+			want:  []sseEvent{{Data: "x"}},
+		},
+		{
+			name:  "empty stream",
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := newSSEReader(strings.NewReader(tt.input))
+			var got []sseEvent
+			for {
+				ev, err := events.next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("next() failed: %v", err)
+				}
+				got = append(got, *ev)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
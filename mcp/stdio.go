@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// stdioShutdownGrace is how long the server gets to exit after each step
+// of the shutdown sequence: closing stdin, then SIGTERM, then SIGKILL.
+const stdioShutdownGrace = 2 * time.Second
+
+// stdioTransport runs the server as a subprocess and exchanges
+// newline-delimited JSON-RPC messages over its stdin and stdout.
+type stdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	mu     sync.Mutex
+	stream *messageStream
+	logf   func(format string, v ...interface{})
+	stderr io.Writer
+}
+
+func newStdioTransport(command []string, stderr io.Writer, logf func(format string, v ...interface{})) (*stdioTransport, error) {
+	if len(command) == 0 {
+		return nil, errors.New("empty server command")
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	cmd.Stderr = stderr
+	cmd.WaitDelay = stdioShutdownGrace
+	detachProcessGroup(cmd)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
+
+	t := &stdioTransport{
+		cmd:    cmd,
+		stdin:  stdin,
+		stream: newMessageStream(),
+		logf:   logf,
+		stderr: stderr,
+	}
+	go t.readMessages(stdout)
+	return t, nil
+}
+
+func (t *stdioTransport) readMessages(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			msg, perr := parseMessage(line)
+			if perr != nil {
+				t.logf("Ignoring non JSON-RPC output from server: %s", line)
+			} else if !t.stream.deliver(msg) {
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("server closed its stdout")
+			}
+			t.stream.finish(err)
+			return
+		}
+	}
+}
+
+func (t *stdioTransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	if err := t.Send(ctx, req); err != nil {
+		return nil, err
+	}
+	return t.stream.await(ctx, idKey(req.ID), handle)
+}
+
+func (t *stdioTransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	return t.stream.listen(ctx, handle)
+}
+
+func (t *stdioTransport) Send(ctx context.Context, msg *JSONRPCMessage) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.stdin.Write(append(msgBytes, '\n')); err != nil {
+		return fmt.Errorf("failed to write to server stdin: %w", err)
+	}
+	return nil
+}
+
+// Close shuts the server down the way the stdio transport asks for: close
+// its stdin, and only signal it when it does not exit by itself.
+func (t *stdioTransport) Close() error {
+	t.stdin.Close()
+	if !t.waitStdout() {
+		t.cmd.Process.Signal(syscall.SIGTERM)
+		if !t.waitStdout() {
+			t.cmd.Process.Kill()
+		}
+	}
+	t.stream.close()
+
+	err := t.cmd.Wait()
+	// Wait has copied all of stderr, but a writer that works line by line
+	// may still hold a last line that lacks its newline
+	if f, ok := t.stderr.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// the server's exit status after being asked to stop is not our concern
+		return nil
+	}
+	return err
+}
+
+func (t *stdioTransport) waitStdout() bool {
+	timer := time.NewTimer(stdioShutdownGrace)
+	defer timer.Stop()
+	for {
+		select {
+		case <-t.stream.done:
+			return true
+		case <-t.stream.in:
+			// nobody is waiting for messages any more
+		case <-timer.C:
+			return false
+		}
+	}
+}
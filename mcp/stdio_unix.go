@@ -0,0 +1,14 @@
+//go:build unix
+
+package mcp
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcessGroup keeps a Ctrl-C in the terminal from reaching the server
+// directly, so that it is still around to be shut down in order.
+func detachProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,31 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Subscribe asks the server to send notifications/resources/updated
+// whenever the resource at uri changes.
+func (c *Client) Subscribe(ctx context.Context, uri string) error {
+	return c.request(ctx, "resources/subscribe", map[string]interface{}{"uri": uri}, nil)
+}
+
+func (c *Client) Unsubscribe(ctx context.Context, uri string) error {
+	return c.request(ctx, "resources/unsubscribe", map[string]interface{}{"uri": uri}, nil)
+}
+
+// UpdatedResource returns the URI named by a notifications/resources/updated
+// message, or false for any other message.
+func UpdatedResource(msg *JSONRPCMessage) (string, bool) {
+	if msg.Method != "notifications/resources/updated" {
+		return "", false
+	}
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.URI == "" {
+		return "", false
+	}
+	return params.URI, true
+}
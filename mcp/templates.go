@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yosida95/uritemplate/v3"
+)
+
+func (c *Client) ListResourceTemplates(ctx context.Context) ([]ResourceTemplate, error) {
+	items, _, err := c.ListResourceTemplatesPages(ctx, PageOptions{})
+	return items, err
+}
+
+func (c *Client) ListResourceTemplatesPages(ctx context.Context, opts PageOptions) ([]ResourceTemplate, string, error) {
+	return listPages[ResourceTemplate](ctx, c, "resources/templates/list", "resourceTemplates", opts)
+}
+
+// ExpandURITemplate expands an RFC 6570 URI template with vars. Variables
+// of the template that are missing from vars expand to nothing, as the RFC
+// asks, and are returned so callers can warn about them; a variable the
+// template does not use is an error.
+func ExpandURITemplate(template string, vars map[string]string) (uri string, missing []string, err error) {
+	tmpl, err := uritemplate.New(template)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URI template %q: %w", template, err)
+	}
+
+	known := map[string]bool{}
+	for _, name := range tmpl.Varnames() {
+		known[name] = true
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	var unknown []string
+	values := uritemplate.Values{}
+	for name, value := range vars {
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		values.Set(name, uritemplate.String(value))
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", nil, fmt.Errorf("URI template %q has no variable %s", template, strings.Join(unknown, ", "))
+	}
+
+	uri, err = tmpl.Expand(values)
+	if err != nil {
+		return "", nil, err
+	}
+	return uri, missing, nil
+}
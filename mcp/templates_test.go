@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExpandURITemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		vars        map[string]string
+		wantURI     string
+		wantMissing []string
+		wantErr     string
+	}{
+		{
+			name:     "all variables given",
+			template: "file:///{dir}/{name}",
+			vars:     map[string]string{"dir": "docs", "name": "a.txt"},
+			wantURI:  "file:///docs/a.txt",
+		},
+		{
+			name:     "values are escaped",
+			template: "db://users/{id}",
+			vars:     map[string]string{"id": "a b/c"},
+			wantURI:  "db://users/a%20b%2Fc",
+		},
+		{
+			name:     "reserved expansion",
+			template: "file:///{+path}",
+			vars:     map[string]string{"path": "docs/a.txt"},
+			wantURI:  "file:///docs/a.txt",
+		},
+		{
+			name:        "missing variable expands to nothing",
+			template:    "file:///{dir}/{name}",
+			vars:        map[string]string{"name": "a.txt"},
+			wantURI:     "file:////a.txt",
+			wantMissing: []string{"dir"},
+		},
+		{
+			name:        "missing query variables are dropped",
+			template:    "search://{?q,page}",
+			vars:        map[string]string{"q": "mcp"},
+			wantURI:     "search://?q=mcp",
+			wantMissing: []string{"page"},
+		},
+		{
+			name:        "no variables given",
+			template:    "file:///{dir}/{name}",
+			wantURI:     "file:////",
+			wantMissing: []string{"dir", "name"},
+		},
+		{
+			name:     "unknown variable",
+			template: "file:///{name}",
+			vars:     map[string]string{"name": "a.txt", "nmae": "b.txt"},
+			wantErr:  `URI template "file:///{name}" has no variable nmae`,
+		},
+		{
+			name:     "unknown variables are sorted",
+			template: "file:///{name}",
+			vars:     map[string]string{"name": "a", "z": "1", "b": "2"},
+			wantErr:  "has no variable b, z",
+		},
+		{
+			name:     "invalid template",
+			template: "file:///{name",
+			wantErr:  "invalid URI template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, missing, err := ExpandURITemplate(tt.template, tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandURITemplate failed: %v", err)
+			}
+			if uri != tt.wantURI {
+				t.Errorf("uri = %q, want %q", uri, tt.wantURI)
+			}
+			if fmt.Sprint(missing) != fmt.Sprint(tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
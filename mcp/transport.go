@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Transport carries JSON-RPC messages between the client and one MCP server.
+type Transport interface {
+	// RoundTrip sends req and waits for the response carrying the same id.
+	// Any other message that arrives in the meantime is passed to handle.
+	RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error)
+	// Send delivers a notification or a response, which get no reply.
+	Send(ctx context.Context, msg *JSONRPCMessage) error
+	// Listen passes every message the server sends on its own initiative
+	// to handle until ctx is done or the server ends the stream.
+	Listen(ctx context.Context, handle func(*JSONRPCMessage)) error
+	Close() error
+}
+
+// messageStream hands messages read by a background goroutine to whoever
+// is waiting for a response. It backs the transports that keep a single
+// long-lived connection open.
+type messageStream struct {
+	in     chan *JSONRPCMessage
+	done   chan struct{}
+	closed chan struct{}
+	once   sync.Once
+	err    error
+}
+
+func newMessageStream() *messageStream {
+	return &messageStream{
+		in:     make(chan *JSONRPCMessage, 64),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+}
+
+// deliver queues msg and reports false once the stream has been closed.
+func (s *messageStream) deliver(msg *JSONRPCMessage) bool {
+	select {
+	case s.in <- msg:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
+
+// finish marks the end of the incoming messages.
+func (s *messageStream) finish(err error) {
+	if err == nil {
+		err = errors.New("connection closed by server")
+	}
+	s.err = err
+	close(s.done)
+}
+
+// close stops the reader from delivering any more messages.
+func (s *messageStream) close() {
+	s.once.Do(func() { close(s.closed) })
+}
+
+func (s *messageStream) await(ctx context.Context, id string, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	for {
+		select {
+		case msg := <-s.in:
+			if msg.isResponse() && idKey(msg.ID) == id {
+				return msg, nil
+			}
+			handle(msg)
+		case <-s.done:
+			// drain whatever the reader queued before it stopped
+			select {
+			case msg := <-s.in:
+				if msg.isResponse() && idKey(msg.ID) == id {
+					return msg, nil
+				}
+				handle(msg)
+				continue
+			default:
+			}
+			return nil, s.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *messageStream) listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	// no response carries an empty id, so await only returns on error
+	_, err := s.await(ctx, "", handle)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
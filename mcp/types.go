@@ -0,0 +1,156 @@
+package mcp
+
+import "encoding/json"
+
+// Implementation names a client or server and its version.
+type Implementation struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version"`
+}
+
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+	// Raw is the result as the server sent it, including the capabilities
+	// and options that ServerCapabilities does not know about.
+	Raw json.RawMessage `json:"-"`
+}
+
+// ServerCapabilities lists what the server supports. A nil field means the
+// server did not advertise that capability.
+type ServerCapabilities struct {
+	Completions  *struct{}              `json:"completions,omitempty"`
+	Experimental map[string]interface{} `json:"experimental,omitempty"`
+	Logging      *struct{}              `json:"logging,omitempty"`
+	Prompts      *ListChangedCapability `json:"prompts,omitempty"`
+	Resources    *ResourcesCapability   `json:"resources,omitempty"`
+	Tools        *ListChangedCapability `json:"tools,omitempty"`
+}
+
+type ListChangedCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+type ResourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+type Tool struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  map[string]interface{} `json:"annotations,omitempty"`
+	Meta         map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type Prompt struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Arguments   []PromptArgument       `json:"arguments,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type Resource struct {
+	URI         string                 `json:"uri"`
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	MimeType    string                 `json:"mimeType,omitempty"`
+	Size        *int64                 `json:"size,omitempty"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// ResourceContents is the content of a resource, either Text or a base64
+// encoded Blob.
+type ResourceContents struct {
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Text     string                 `json:"text,omitempty"`
+	Blob     string                 `json:"blob,omitempty"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// Content is a content block of a tool result or a prompt message. Type
+// tells which of the other fields are set: "text", "image", "audio",
+// "resource_link" or "resource".
+type Content struct {
+	Type        string                 `json:"type"`
+	Text        string                 `json:"text,omitempty"`
+	Data        string                 `json:"data,omitempty"`
+	MimeType    string                 `json:"mimeType,omitempty"`
+	URI         string                 `json:"uri,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Resource    *ResourceContents      `json:"resource,omitempty"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type CallToolResult struct {
+	Content           []Content              `json:"content"`
+	StructuredContent interface{}            `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type ReadResourceResult struct {
+	Contents []ResourceContents     `json:"contents"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type GetPromptResult struct {
+	Description string                 `json:"description,omitempty"`
+	Messages    []PromptMessage        `json:"messages"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+type ResourceTemplate struct {
+	URITemplate string                 `json:"uriTemplate"`
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	MimeType    string                 `json:"mimeType,omitempty"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Meta        map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// CompletionReference names what an argument is completed for: a prompt
+// (Type "ref/prompt" with Name) or a resource template (Type "ref/resource"
+// with URI).
+type CompletionReference struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   *int     `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+type CompleteResult struct {
+	Completion Completion             `json:"completion"`
+	Meta       map[string]interface{} `json:"_meta,omitempty"`
+}
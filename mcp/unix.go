@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// UseUnixSocket sends every request of the client over the Unix domain
+// socket at path, whatever host the request URLs name.
+func (c *Client) UseUnixSocket(path string) {
+	c.UnixSocket = path
+	c.HTTPClient = &http.Client{
+		Transport: &http.Transport{DialContext: c.dialUnix},
+	}
+}
+
+func (c *Client) dialUnix(ctx context.Context, _, _ string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "unix", c.UnixSocket)
+}
+
+// parseUnixHost splits a host such as unix:///run/mcp.sock:/mcp into the
+// socket path and the HTTP URL of the endpoint served on it.
+func parseUnixHost(host string) (socket, endpoint string, ok bool) {
+	rest, ok := strings.CutPrefix(host, "unix://")
+	if !ok {
+		return "", "", false
+	}
+
+	socket, path, found := strings.Cut(rest, ":")
+	if !found || path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return socket, "http://localhost" + path, true
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketTransport exchanges one JSON-RPC message per text frame over a
+// single WebSocket connection to Client.Host.
+type websocketTransport struct {
+	c      *Client
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	stream *messageStream
+}
+
+// connect dials the server the first time the transport is used.
+func (t *websocketTransport) connect(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn != nil {
+		return nil
+	}
+
+	dialer := websocket.Dialer{
+		Subprotocols:     []string{"mcp"},
+		HandshakeTimeout: 45 * time.Second,
+	}
+	if t.c.UnixSocket != "" {
+		dialer.NetDialContext = t.c.dialUnix
+	}
+	conn, resp, err := dialer.DialContext(ctx, t.c.Host, nil)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			return fmt.Errorf("websocket handshake failed: %w", newHTTPError(resp))
+		}
+		return err
+	}
+
+	t.conn = conn
+	t.stream = newMessageStream()
+	go t.readMessages()
+	return nil
+}
+
+func (t *websocketTransport) readMessages() {
+	for {
+		kind, data, err := t.conn.ReadMessage()
+		if err != nil {
+			t.stream.finish(err)
+			return
+		}
+		if kind != websocket.TextMessage {
+			t.c.logf("Ignoring non-text websocket frame")
+			continue
+		}
+
+		msg, err := parseMessage(data)
+		if err != nil {
+			t.c.logf("Ignoring malformed websocket message: %v", err)
+			continue
+		}
+		if !t.stream.deliver(msg) {
+			return
+		}
+	}
+}
+
+func (t *websocketTransport) RoundTrip(ctx context.Context, req *JSONRPCMessage, handle func(*JSONRPCMessage)) (*JSONRPCMessage, error) {
+	if err := t.Send(ctx, req); err != nil {
+		return nil, err
+	}
+	return t.stream.await(ctx, idKey(req.ID), handle)
+}
+
+func (t *websocketTransport) Send(ctx context.Context, msg *JSONRPCMessage) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		t.conn.SetWriteDeadline(deadline)
+		defer t.conn.SetWriteDeadline(time.Time{})
+	}
+	return t.conn.WriteMessage(websocket.TextMessage, msgBytes)
+}
+
+func (t *websocketTransport) Listen(ctx context.Context, handle func(*JSONRPCMessage)) error {
+	if err := t.connect(ctx); err != nil {
+		return err
+	}
+	return t.stream.listen(ctx, handle)
+}
+
+func (t *websocketTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return nil
+	}
+
+	t.stream.close()
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	t.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	return t.conn.Close()
+}